@@ -0,0 +1,161 @@
+// Command localserver serves the Lambda handlers over plain HTTP, translating
+// each request into the API Gateway proxy event the functions receive in AWS.
+// It uses the database configured through the DB_* environment variables.
+//
+// Usage:
+//
+//	localserver [-addr :8080]
+package main
+
+import (
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"fazendadojuca.com.br/internal/animals"
+	"fazendadojuca.com.br/internal/api"
+	"fazendadojuca.com.br/internal/health"
+	"fazendadojuca.com.br/internal/insemination"
+	"fazendadojuca.com.br/internal/lookup"
+	"fazendadojuca.com.br/internal/reproduction"
+	"fazendadojuca.com.br/internal/weighing"
+)
+
+type route struct {
+	resource string
+	handler  api.HandlerFunc
+}
+
+// routes mirrors the http events of serverless.yml.
+var routes = []route{
+	{"/animals", animals.Handler},
+	{"/animals/inbreeding", animals.Handler},
+	{"/animals/external", animals.Handler},
+	{"/animals/{id}/pedigree", animals.Handler},
+	{"/animals/{id}/progeny", animals.Handler},
+	{"/animals/{id}/history", animals.Handler},
+	{"/animals/{id}/planner", animals.Handler},
+	{"/animals/{id}/restore", animals.Handler},
+	{"/animals/{id}/purge", animals.Handler},
+	{"/breed", lookup.BreedHandler},
+	{"/gender", lookup.GenderHandler},
+	{"/health", health.Handler},
+	{"/health/withdrawal", health.Handler},
+	{"/insemination", insemination.Handler},
+	{"/insemination/rates", insemination.Handler},
+	{"/purity", lookup.PurityLevelHandler},
+	{"/reproduction", reproduction.Handler},
+	{"/reproduction/due", reproduction.Handler},
+	{"/weighing", weighing.Handler},
+	{"/weighing/growth", weighing.Handler},
+}
+
+// match reports whether path fits resource, returning its {param} values.
+func match(resource, path string) (map[string]string, bool) {
+	rs := strings.Split(strings.Trim(resource, "/"), "/")
+	ps := strings.Split(strings.Trim(path, "/"), "/")
+	if len(rs) != len(ps) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, r := range rs {
+		if strings.HasPrefix(r, "{") && strings.HasSuffix(r, "}") {
+			params[strings.Trim(r, "{}")] = ps[i]
+		} else if r != ps[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func toRequest(r *http.Request, resource string, params map[string]string) (api.Request, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return api.Request{}, err
+	}
+	req := api.Request{
+		Resource:                        resource,
+		Path:                            r.URL.Path,
+		HTTPMethod:                      r.Method,
+		Headers:                         map[string]string{},
+		MultiValueHeaders:               r.Header,
+		QueryStringParameters:           map[string]string{},
+		MultiValueQueryStringParameters: r.URL.Query(),
+		PathParameters:                  params,
+		Body:                            string(body),
+	}
+	for k := range r.Header {
+		req.Headers[k] = r.Header.Get(k)
+	}
+	for k, v := range r.URL.Query() {
+		req.QueryStringParameters[k] = v[0]
+	}
+	req.RequestContext.HTTPMethod = r.Method
+	req.RequestContext.ResourcePath = resource
+	req.RequestContext.Identity.SourceIP = r.RemoteAddr
+	return req, nil
+}
+
+func writeResponse(w http.ResponseWriter, resp *api.Response) {
+	for k, vs := range resp.MultiValueHeaders {
+		for _, v := range vs {
+			w.Header().Add(k, v)
+		}
+	}
+	for k, v := range resp.Headers {
+		w.Header().Set(k, v)
+	}
+	body := []byte(resp.Body)
+	if resp.IsBase64Encoded {
+		var err error
+		if body, err = base64.StdEncoding.DecodeString(resp.Body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	w.Write(body)
+}
+
+// gatewayError writes the responses API Gateway itself produces.
+func gatewayError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "{\"message\":%q}", msg)
+}
+
+func serve(w http.ResponseWriter, r *http.Request) {
+	for _, rt := range routes {
+		params, ok := match(rt.resource, r.URL.Path)
+		if !ok {
+			continue
+		}
+		req, err := toRequest(r, rt.resource, params)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		resp, err := rt.handler(req)
+		if err != nil || resp == nil {
+			// API Gateway answers a failed invocation with a 502.
+			log.Printf("%s %s: %v", r.Method, r.URL.Path, err)
+			gatewayError(w, http.StatusBadGateway, "Internal server error")
+			return
+		}
+		log.Printf("%s %s %d", r.Method, r.URL.RequestURI(), resp.StatusCode)
+		writeResponse(w, resp)
+		return
+	}
+	gatewayError(w, http.StatusForbidden, "Missing Authentication Token")
+}
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	flag.Parse()
+	log.Printf("listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, http.HandlerFunc(serve)))
+}
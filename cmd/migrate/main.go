@@ -0,0 +1,68 @@
+// Command migrate applies the schema migrations in migrations/ to the database
+// configured through the DB_* environment variables, using the migrations of
+// the backend's dialect.
+//
+// Usage:
+//
+//	migrate [-dry-run] up [-to N]
+//	migrate [-dry-run] down [-steps N]
+//	migrate [-dry-run] baseline -to N
+//	migrate status
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"fazendadojuca.com.br/internal/database"
+	"fazendadojuca.com.br/internal/migrate"
+	"fazendadojuca.com.br/migrations"
+)
+
+func main() {
+	log.SetFlags(0)
+	dryRun := flag.Bool("dry-run", false, "print the statements instead of running them")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: migrate [-dry-run] up [-to N] | down [-steps N] | baseline -to N | status")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	db := database.DB()
+	ms, err := migrate.Load(migrations.For(database.Current().Dialect()))
+	if err != nil {
+		log.Fatal(err)
+	}
+	r := &migrate.Runner{DB: db, Migrations: ms, Out: os.Stdout, DryRun: *dryRun}
+
+	cmd := flag.NewFlagSet(flag.Arg(0), flag.ExitOnError)
+	to := cmd.Int("to", 0, "last version to apply (default all)")
+	steps := cmd.Int("steps", 1, "number of migrations to revert")
+	cmd.Parse(flag.Args()[1:])
+
+	switch flag.Arg(0) {
+	case "up":
+		err = r.Up(*to)
+	case "down":
+		err = r.Down(*steps)
+	case "baseline":
+		if *to == 0 {
+			log.Fatal("baseline needs -to")
+		}
+		err = r.Baseline(*to)
+	case "status":
+		err = r.Status()
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
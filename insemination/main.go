@@ -0,0 +1,11 @@
+package main
+
+import (
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"fazendadojuca.com.br/internal/insemination"
+)
+
+func main() {
+	lambda.Start(insemination.Handler)
+}
@@ -0,0 +1,559 @@
+// Package animals implements the animals function: CRUD over the herd plus the
+// pedigree, progeny, inbreeding, mating planner and history endpoints.
+package animals
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"fazendadojuca.com.br/internal/api"
+	"fazendadojuca.com.br/internal/audit"
+	"fazendadojuca.com.br/internal/database"
+	"fazendadojuca.com.br/internal/date"
+	"fazendadojuca.com.br/internal/lookup"
+)
+
+type gender struct {
+	ID   int    `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	// Sex is sexMale, sexFemale or nil.
+	Sex *string `json:"-"`
+}
+
+const (
+	sexMale   = lookup.SexMale
+	sexFemale = lookup.SexFemale
+)
+
+// is reports whether g is of the given sex.
+func (g gender) is(sex string) bool {
+	return g.Sex != nil && *g.Sex == sex
+}
+
+type breed struct {
+	ID   int    `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	// Group and Unknown decide which parents pass their purity on, see compatibleBreed.
+	Group   *string `json:"-"`
+	Unknown bool    `json:"-"`
+}
+
+type purityLevel struct {
+	ID    int    `json:"id,omitempty"`
+	Level string `json:"level,omitempty"`
+}
+
+type animal struct {
+	ID          int         `json:"id,omitempty"`
+	Name        string      `json:"name"`
+	Gender      gender      `json:"gender"`
+	Breed       breed       `json:"breed"`
+	PurityLevel purityLevel `json:"purity_level"`
+	Number      string      `json:"number"`
+	Registry    string      `json:"registry"`
+	Origin      string      `json:"origin"`
+	// Father and Mother are herd animals, ExternalFather and ExternalMother
+	// external parents. Either may be set for each parent, or neither when unknown.
+	Father         *int       `json:"father"`
+	Mother         *int       `json:"mother"`
+	ExternalFather *int       `json:"external_father"`
+	ExternalMother *int       `json:"external_mother"`
+	Insemination   int        `json:"insemination"`
+	InseminationID *int       `json:"insemination_id"`
+	Birth          date.Date  `json:"birth"`
+	Death          *date.Date `json:"death"`
+	// DeletedAt is set once the animal is deleted. It stays in the pedigree of
+	// its descendants until purged.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	api.Meta
+}
+
+// service adapts the animal functions to api.Service.
+type service struct{}
+
+func (service) FetchOne(id int) (*animal, error) { return serviceFetchOne(id) }
+func (service) FetchAll() ([]*animal, error)     { return serviceFetchAll() }
+func (service) Create(a *animal, actor audit.Actor) (*animal, error) {
+	return serviceCreate(a, actor)
+}
+func (service) Update(a *animal, actor audit.Actor) (*animal, error) {
+	return serviceUpdate(a, actor)
+}
+func (service) Delete(id, version int, actor audit.Actor) error {
+	return serviceDelete(id, version, actor)
+}
+
+var crud = api.Handler[animal](service{})
+
+// Handler is the API Gateway handler of the animals function.
+var Handler = api.Recover(handler)
+
+func handler(req api.Request) (*api.Response, error) {
+	switch {
+	case strings.HasSuffix(req.Path, "/external"):
+		return externalCRUD(req)
+	case strings.HasSuffix(req.Path, "/restore"):
+		return postRestore(req)
+	case strings.HasSuffix(req.Path, "/purge"):
+		return deletePurge(req)
+	}
+	if req.HTTPMethod == "GET" {
+		switch {
+		case strings.HasSuffix(req.Path, "/pedigree"):
+			return getPedigree(req)
+		case strings.HasSuffix(req.Path, "/progeny"):
+			return getProgeny(req)
+		case strings.HasSuffix(req.Path, "/inbreeding"):
+			return getInbreeding(req)
+		case strings.HasSuffix(req.Path, "/planner"):
+			return getPlanner(req)
+		case strings.HasSuffix(req.Path, "/history"):
+			return getHistory(req)
+		case req.QueryStringParameters["id"] == "":
+			return getList(req)
+		default:
+			return getOne(req)
+		}
+	}
+	return crud(req)
+}
+
+func getList(req api.Request) (*api.Response, error) {
+	q, err := parseListQuery(req)
+	if err != nil {
+		return api.Fail(err)
+	}
+	result, err := serviceList(q)
+	if err != nil {
+		return api.Fail(err)
+	}
+	return api.JSON(http.StatusOK, result)
+}
+
+func getPedigree(req api.Request) (*api.Response, error) {
+	id, err := api.PathID(req)
+	if err != nil {
+		return api.Fail(err)
+	}
+	depth, err := api.QueryInt(req, "depth", defaultPedigreeDepth, maxPedigreeDepth)
+	if err != nil {
+		return api.Fail(err)
+	}
+	deleted, err := includeDeleted(req)
+	if err != nil {
+		return api.Fail(err)
+	}
+	result, err := servicePedigree(id, depth, deleted)
+	if err != nil {
+		return api.Fail(err)
+	}
+	return api.JSON(http.StatusOK, result)
+}
+
+func getProgeny(req api.Request) (*api.Response, error) {
+	id, err := api.PathID(req)
+	if err != nil {
+		return api.Fail(err)
+	}
+	generations, err := api.QueryInt(req, "generations", defaultProgenyGenerations, maxProgenyGenerations)
+	if err != nil {
+		return api.Fail(err)
+	}
+	deleted, err := includeDeleted(req)
+	if err != nil {
+		return api.Fail(err)
+	}
+	result, err := serviceProgeny(id, generations, deleted)
+	if err != nil {
+		return api.Fail(err)
+	}
+	return api.JSON(http.StatusOK, result)
+}
+
+func getInbreeding(req api.Request) (*api.Response, error) {
+	sire, err := strconv.Atoi(req.QueryStringParameters["sire"])
+	if err != nil {
+		return api.Fail(api.Validation("Invalid Sire"))
+	}
+	dam, err := strconv.Atoi(req.QueryStringParameters["dam"])
+	if err != nil {
+		return api.Fail(api.Validation("Invalid Dam"))
+	}
+	deleted, err := includeDeleted(req)
+	if err != nil {
+		return api.Fail(err)
+	}
+	result, err := serviceInbreeding(sire, dam, deleted)
+	if err != nil {
+		return api.Fail(err)
+	}
+	if !result.Allowed {
+		return api.JSON(http.StatusConflict, result)
+	}
+	return api.JSON(http.StatusOK, result)
+}
+
+func getPlanner(req api.Request) (*api.Response, error) {
+	id, err := api.PathID(req)
+	if err != nil {
+		return api.Fail(err)
+	}
+	target, err := strconv.Atoi(req.QueryStringParameters["target"])
+	if err != nil {
+		return api.Fail(api.Validation("Invalid Target"))
+	}
+	breedID := 0
+	if v := req.QueryStringParameters["breed"]; v != "" {
+		breedID, err = strconv.Atoi(v)
+		if err != nil {
+			return api.Fail(api.Validation("Invalid Breed"))
+		}
+	}
+	result, err := servicePlanner(id, target, breedID)
+	if err != nil {
+		return api.Fail(err)
+	}
+	return api.JSON(http.StatusOK, result)
+}
+
+const animalSelect = `
+	SELECT
+		a.id,
+		a.name,
+		g.id,
+		g.name,
+		g.sex,
+		b.id,
+		b.name,
+		b.breed_group,
+		b.unknown,
+		p.id,
+		p.level,
+		a.number,
+		a.registry,
+		a.origin,
+		a.father,
+		a.mother,
+		a.external_father,
+		a.external_mother,
+		a.insemination,
+		a.insemination_id,
+		a.birth,
+		a.death,
+		a.deleted_at,
+		a.version,
+		a.updated_at
+	FROM animal a
+		JOIN gender g ON g.id = a.gender_id
+		JOIN breed b ON b.id  = a.breed_id
+		JOIN purity_level p ON p.id = a.purity_level_id`
+
+func scanAnimal(s database.Scanner) (*animal, error) {
+	a := new(animal)
+	err := s.Scan(
+		&a.ID,
+		&a.Name,
+		&a.Gender.ID,
+		&a.Gender.Name,
+		&a.Gender.Sex,
+		&a.Breed.ID,
+		&a.Breed.Name,
+		&a.Breed.Group,
+		&a.Breed.Unknown,
+		&a.PurityLevel.ID,
+		&a.PurityLevel.Level,
+		&a.Number,
+		&a.Registry,
+		&a.Origin,
+		&a.Father,
+		&a.Mother,
+		&a.ExternalFather,
+		&a.ExternalMother,
+		&a.Insemination,
+		&a.InseminationID,
+		&a.Birth,
+		&a.Death,
+		&a.DeletedAt,
+		&a.Version,
+		&a.UpdatedAt,
+	)
+	return a, err
+}
+
+func serviceFetchOne(id int) (*animal, error) {
+	return fetchAnimal(id, false)
+}
+
+// fetchAnimal loads a single animal, which may be a deleted one when includeDeleted is set.
+func fetchAnimal(id int, includeDeleted bool) (*animal, error) {
+	return fetchAnimalIn(database.DB(), id, includeDeleted)
+}
+
+func fetchAnimalIn(q database.Querier, id int, includeDeleted bool) (*animal, error) {
+	if id == 0 {
+		return nil, api.Validation("Invalid ID")
+	}
+	query := animalSelect + " WHERE a.id= ?"
+	if !includeDeleted {
+		query += " AND a.deleted_at IS NULL"
+	}
+	row := q.QueryRow(query, id)
+	a, err := scanAnimal(row)
+	if err == sql.ErrNoRows {
+		return nil, api.NotFound("Animal Not Found")
+	}
+	if err != nil {
+		return nil, api.Internal(err)
+	}
+	return a, nil
+}
+
+// queryAnimals runs an animalSelect based query and scans every row.
+func queryAnimals(query string, args ...interface{}) ([]*animal, error) {
+	return queryAnimalsIn(database.DB(), query, args...)
+}
+
+func queryAnimalsIn(q database.Querier, query string, args ...interface{}) ([]*animal, error) {
+	results, err := q.Query(query, args...)
+	if err != nil {
+		return nil, api.Internal(err)
+	}
+	defer results.Close()
+	as := []*animal{}
+	for results.Next() {
+		a, err := scanAnimal(results)
+		if err != nil {
+			return nil, api.Internal(err)
+		}
+		as = append(as, a)
+	}
+	if err := results.Err(); err != nil {
+		return nil, api.Internal(err)
+	}
+	return as, nil
+}
+
+// lockAnimal loads an animal, deleted or not, within tx and keeps others from
+// writing it until tx ends. Only the animal row is locked, not its lookups.
+func lockAnimal(tx database.Querier, id int) (*animal, error) {
+	if id == 0 {
+		return nil, api.Validation("Invalid ID")
+	}
+	err := tx.QueryRow("SELECT id FROM animal WHERE id = ?"+database.ForUpdate(), id).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, api.NotFound("Animal Not Found")
+	}
+	if err != nil {
+		return nil, api.Internal(err)
+	}
+	return fetchAnimalIn(tx, id, true)
+}
+
+// checkVersion fails when a is not at version, 0 matching any.
+func checkVersion(a *animal, version int) error {
+	if version != 0 && version != a.Version {
+		return api.PreconditionFailed("Version Mismatch")
+	}
+	return nil
+}
+
+// record keeps a change to an animal in the audit log, within tx.
+func record(tx database.Querier, actor audit.Actor, action string, before, after *animal) error {
+	if err := audit.Record(tx, actor, "animal", action, before, after); err != nil {
+		return api.Internal(err)
+	}
+	return nil
+}
+
+func serviceFetchAll() ([]*animal, error) {
+	return queryAnimals(animalSelect + " WHERE a.deleted_at IS NULL")
+}
+
+func serviceCreate(a *animal, actor audit.Actor) (*animal, error) {
+	err := validateAnimal(a)
+	if err != nil {
+		return nil, err
+	}
+	err = resolvePurity(a)
+	if err != nil {
+		return nil, err
+	}
+	var result *animal
+	err = database.InTx(func(tx *sql.Tx) error {
+		res, err := tx.Exec(`
+	INSERT INTO animal (
+		name,
+		gender_id,
+		breed_id,
+		purity_level_id,
+		number,
+		registry,
+		origin,
+		father,
+		mother,
+		external_father,
+		external_mother,
+		insemination,
+		insemination_id,
+		birth,
+		death,
+		version,
+		updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 1, CURRENT_TIMESTAMP);`,
+			&a.Name,
+			&a.Gender.ID,
+			&a.Breed.ID,
+			&a.PurityLevel.ID,
+			&a.Number,
+			&a.Registry,
+			&a.Origin,
+			&a.Father,
+			&a.Mother,
+			&a.ExternalFather,
+			&a.ExternalMother,
+			&a.Insemination,
+			&a.InseminationID,
+			&a.Birth,
+			&a.Death)
+		if database.IsForeignKeyError(err) {
+			return api.Validation("Invalid Reference")
+		}
+		if err != nil {
+			return api.Internal(err)
+		}
+		aID, err := res.LastInsertId()
+		if err != nil {
+			return api.Internal(err)
+		}
+		if err := markCalved(tx, a, actor); err != nil {
+			return err
+		}
+		result, err = fetchAnimalIn(tx, int(aID), false)
+		if err != nil {
+			return err
+		}
+		return record(tx, actor, audit.ActionCreate, nil, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func serviceUpdate(a *animal, actor audit.Actor) (*animal, error) {
+	if a.ID == 0 {
+		return nil, api.Validation("Invalid ID")
+	}
+	err := validateAnimal(a)
+	if err != nil {
+		return nil, err
+	}
+	err = resolvePurity(a)
+	if err != nil {
+		return nil, err
+	}
+	var result *animal
+	err = database.InTx(func(tx *sql.Tx) error {
+		before, err := lockAnimal(tx, a.ID)
+		if err != nil {
+			return err
+		}
+		if before.DeletedAt != nil {
+			return api.NotFound("Animal Not Found")
+		}
+		if err := checkVersion(before, a.Version); err != nil {
+			return err
+		}
+		_, err = tx.Exec(`
+	UPDATE animal SET
+		name = ?,
+		gender_id = ?,
+		breed_id = ?,
+		purity_level_id = ?,
+		number = ?,
+		registry = ?,
+		origin = ?,
+		father = ?,
+		mother = ?,
+		external_father = ?,
+		external_mother = ?,
+		insemination = ?,
+		insemination_id = ?,
+		birth = ?,
+		death = ?,
+		version = version + 1,
+		updated_at = CURRENT_TIMESTAMP
+	WHERE id = ?;`,
+			&a.Name,
+			&a.Gender.ID,
+			&a.Breed.ID,
+			&a.PurityLevel.ID,
+			&a.Number,
+			&a.Registry,
+			&a.Origin,
+			&a.Father,
+			&a.Mother,
+			&a.ExternalFather,
+			&a.ExternalMother,
+			&a.Insemination,
+			&a.InseminationID,
+			&a.Birth,
+			&a.Death,
+			&a.ID)
+		if database.IsForeignKeyError(err) {
+			return api.Validation("Invalid Reference")
+		}
+		if err != nil {
+			return api.Internal(err)
+		}
+		if err := unmarkCalved(tx, before, a, actor); err != nil {
+			return err
+		}
+		if err := markCalved(tx, a, actor); err != nil {
+			return err
+		}
+		result, err = fetchAnimalIn(tx, a.ID, false)
+		if err != nil {
+			return err
+		}
+		return record(tx, actor, audit.ActionUpdate, before, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// serviceDelete soft deletes an animal, leaving its offspring and records untouched.
+// servicePurge removes it for good.
+func serviceDelete(id, version int, actor audit.Actor) error {
+	return database.InTx(func(tx *sql.Tx) error {
+		before, err := lockAnimal(tx, id)
+		if err != nil {
+			return err
+		}
+		if before.DeletedAt != nil {
+			return api.NotFound("Animal Not Found")
+		}
+		if err := checkVersion(before, version); err != nil {
+			return err
+		}
+		_, err = tx.Exec(`
+	UPDATE animal SET
+		deleted_at = CURRENT_TIMESTAMP,
+		version = version + 1,
+		updated_at = CURRENT_TIMESTAMP
+	WHERE id = ?;`, id)
+		if err != nil {
+			return api.Internal(err)
+		}
+		after, err := fetchAnimalIn(tx, id, true)
+		if err != nil {
+			return err
+		}
+		return record(tx, actor, audit.ActionDelete, before, after)
+	})
+}
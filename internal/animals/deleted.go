@@ -0,0 +1,144 @@
+package animals
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+
+	"fazendadojuca.com.br/internal/api"
+	"fazendadojuca.com.br/internal/audit"
+	"fazendadojuca.com.br/internal/database"
+)
+
+// includeDeleted parses the include_deleted flag, which brings deleted animals back into a response.
+func includeDeleted(req api.Request) (bool, error) {
+	v := req.QueryStringParameters["include_deleted"]
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, api.Validation("Invalid include_deleted")
+	}
+	return b, nil
+}
+
+func getOne(req api.Request) (*api.Response, error) {
+	id, err := strconv.Atoi(req.QueryStringParameters["id"])
+	if err != nil {
+		return api.Fail(api.Validation("Invalid ID"))
+	}
+	deleted, err := includeDeleted(req)
+	if err != nil {
+		return api.Fail(err)
+	}
+	result, err := fetchAnimal(id, deleted)
+	if err != nil {
+		return api.Fail(err)
+	}
+	return api.ETagged(http.StatusOK, result)
+}
+
+func postRestore(req api.Request) (*api.Response, error) {
+	if req.HTTPMethod != "POST" {
+		return api.UnhandledMethod()
+	}
+	id, err := api.PathID(req)
+	if err != nil {
+		return api.Fail(err)
+	}
+	version, err := api.IfMatch(req)
+	if err != nil {
+		return api.Fail(err)
+	}
+	result, err := serviceRestore(id, version, api.Actor(req))
+	if err != nil {
+		return api.Fail(err)
+	}
+	return api.ETagged(http.StatusOK, result)
+}
+
+func deletePurge(req api.Request) (*api.Response, error) {
+	if req.HTTPMethod != "DELETE" {
+		return api.UnhandledMethod()
+	}
+	id, err := api.PathID(req)
+	if err != nil {
+		return api.Fail(err)
+	}
+	version, err := api.IfMatch(req)
+	if err != nil {
+		return api.Fail(err)
+	}
+	err = servicePurge(id, version, api.Actor(req))
+	if err != nil {
+		return api.Fail(err)
+	}
+	return api.JSON(http.StatusOK, nil)
+}
+
+// serviceRestore brings a deleted animal back into the herd.
+func serviceRestore(id, version int, actor audit.Actor) (*animal, error) {
+	var result *animal
+	err := database.InTx(func(tx *sql.Tx) error {
+		before, err := lockAnimal(tx, id)
+		if err != nil {
+			return err
+		}
+		if before.DeletedAt == nil {
+			return api.Conflict("Animal Not Deleted")
+		}
+		if err := checkVersion(before, version); err != nil {
+			return err
+		}
+		_, err = tx.Exec(`
+	UPDATE animal SET
+		deleted_at = NULL,
+		version = version + 1,
+		updated_at = CURRENT_TIMESTAMP
+	WHERE id = ?;`, id)
+		if err != nil {
+			return api.Internal(err)
+		}
+		result, err = fetchAnimalIn(tx, id, false)
+		if err != nil {
+			return err
+		}
+		return record(tx, actor, audit.ActionRestore, before, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// servicePurge removes an animal for good, deleted or not. It is refused while
+// the animal is the parent of another or still referenced by its records.
+func servicePurge(id, version int, actor audit.Actor) error {
+	return database.InTx(func(tx *sql.Tx) error {
+		before, err := lockAnimal(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(before, version); err != nil {
+			return err
+		}
+		children, err := queryAnimalsIn(tx, animalSelect+" WHERE a.father = ? OR a.mother = ?", id, id)
+		if err != nil {
+			return err
+		}
+		if len(children) > 0 {
+			e := api.Conflict("Animal Has Offspring")
+			e.Details = map[string][]*animal{"animal": children}
+			return e
+		}
+		_, err = tx.Exec("DELETE FROM animal WHERE id = ?", id)
+		if database.IsForeignKeyError(err) {
+			return api.Conflict("Animal In Use")
+		}
+		if err != nil {
+			return api.Internal(err)
+		}
+		return record(tx, actor, audit.ActionPurge, before, nil)
+	})
+}
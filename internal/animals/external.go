@@ -0,0 +1,180 @@
+package animals
+
+import (
+	"fazendadojuca.com.br/internal/api"
+	"fazendadojuca.com.br/internal/audit"
+	"fazendadojuca.com.br/internal/database"
+	"fazendadojuca.com.br/internal/repository"
+)
+
+// externalParent is a sire or dam outside the herd, such as a semen-bank bull
+// known only by its registry number.
+type externalParent struct {
+	ID          int         `json:"id,omitempty"`
+	Name        string      `json:"name"`
+	Registry    string      `json:"registry"`
+	Breed       breed       `json:"breed"`
+	PurityLevel purityLevel `json:"purity_level"`
+	api.Meta
+}
+
+var externalParentRepo = repository.New(repository.Entity[externalParent]{
+	Name:    "External Parent",
+	Table:   "external_parent",
+	Columns: []string{"name", "registry", "breed_id", "purity_level_id"},
+	ID:      func(p *externalParent) *int { return &p.ID },
+	Fields: func(p *externalParent) []interface{} {
+		return []interface{}{&p.Name, &p.Registry, &p.Breed.ID, &p.PurityLevel.ID}
+	},
+	References: []repository.Reference{
+		{Table: "animal", Column: "external_father"},
+		{Table: "animal", Column: "external_mother"},
+	},
+})
+
+// externalService serves external parents with the names of their breed and
+// purity level filled in, in the same shape as those of an animal.
+type externalService struct {
+	*repository.Repository[externalParent]
+}
+
+func (s externalService) FetchOne(id int) (*externalParent, error) {
+	return nameOne(s.Repository.FetchOne(id))
+}
+
+func (s externalService) FetchAll() ([]*externalParent, error) {
+	ps, err := s.Repository.FetchAll()
+	if err != nil {
+		return nil, err
+	}
+	return ps, nameExternalParents(ps...)
+}
+
+func (s externalService) Create(p *externalParent, actor audit.Actor) (*externalParent, error) {
+	return nameOne(s.Repository.Create(p, actor))
+}
+
+func (s externalService) Update(p *externalParent, actor audit.Actor) (*externalParent, error) {
+	return nameOne(s.Repository.Update(p, actor))
+}
+
+func nameOne(p *externalParent, err error) (*externalParent, error) {
+	if err != nil {
+		return nil, err
+	}
+	return p, nameExternalParents(p)
+}
+
+// nameExternalParents fills in the breed and purity level names of ps.
+func nameExternalParents(ps ...*externalParent) error {
+	breeds, levels := map[int]string{}, map[int]string{}
+	for _, l := range []struct {
+		query string
+		names map[int]string
+	}{
+		{"SELECT id, name FROM breed", breeds},
+		{"SELECT id, level FROM purity_level", levels},
+	} {
+		if err := lookupNames(l.query, l.names); err != nil {
+			return err
+		}
+	}
+	for _, p := range ps {
+		p.Breed.Name = breeds[p.Breed.ID]
+		p.PurityLevel.Level = levels[p.PurityLevel.ID]
+	}
+	return nil
+}
+
+// lookupNames reads the id and name pairs returned by query into names.
+func lookupNames(query string, names map[int]string) error {
+	db := database.DB()
+	results, err := db.Query(query)
+	if err != nil {
+		return api.Internal(err)
+	}
+	defer results.Close()
+	for results.Next() {
+		var id int
+		var name string
+		if err := results.Scan(&id, &name); err != nil {
+			return api.Internal(err)
+		}
+		names[id] = name
+	}
+	if err := results.Err(); err != nil {
+		return api.Internal(err)
+	}
+	return nil
+}
+
+var externalCRUD = api.Handler[externalParent](externalService{externalParentRepo})
+
+const externalParentSelect = `
+	SELECT
+		e.id,
+		e.name,
+		b.id,
+		b.name,
+		b.breed_group,
+		b.unknown,
+		p.id,
+		p.level
+	FROM external_parent e
+		JOIN breed b ON b.id = e.breed_id
+		JOIN purity_level p ON p.id = e.purity_level_id`
+
+// fetchExternalParents loads the given external parents as founder animals,
+// keyed by their pedigree key (see parentKey).
+func fetchExternalParents(ids []int) (map[int]*animal, error) {
+	as := map[int]*animal{}
+	if len(ids) == 0 {
+		return as, nil
+	}
+	db := database.DB()
+	results, err := db.Query(externalParentSelect+" WHERE e.id IN ("+placeholders(len(ids))+")", intArgs(ids)...)
+	if err != nil {
+		return nil, api.Internal(err)
+	}
+	defer results.Close()
+	for results.Next() {
+		a := new(animal)
+		err := results.Scan(&a.ID, &a.Name, &a.Breed.ID, &a.Breed.Name, &a.Breed.Group, &a.Breed.Unknown,
+			&a.PurityLevel.ID, &a.PurityLevel.Level)
+		if err != nil {
+			return nil, api.Internal(err)
+		}
+		a.ID = -a.ID
+		as[a.ID] = a
+	}
+	if err := results.Err(); err != nil {
+		return nil, api.Internal(err)
+	}
+	return as, nil
+}
+
+// parentKey identifies a parent in pedigree calculations: the animal id of a herd
+// parent, the negated id of an external parent, or 0 when the parent is unknown.
+func parentKey(id, external *int) int {
+	switch {
+	case id != nil:
+		return *id
+	case external != nil:
+		return -*external
+	}
+	return 0
+}
+
+func (a *animal) sire() int { return parentKey(a.Father, a.ExternalFather) }
+func (a *animal) dam() int  { return parentKey(a.Mother, a.ExternalMother) }
+
+// isExternal reports whether key refers to an external parent.
+func isExternal(key int) bool { return key < 0 }
+
+// keyID returns the id of the animal or external parent behind key.
+func keyID(key int) int {
+	if key < 0 {
+		return -key
+	}
+	return key
+}
@@ -0,0 +1,34 @@
+package animals
+
+import (
+	"net/http"
+
+	"fazendadojuca.com.br/internal/api"
+	"fazendadojuca.com.br/internal/audit"
+)
+
+func getHistory(req api.Request) (*api.Response, error) {
+	id, err := api.PathID(req)
+	if err != nil {
+		return api.Fail(err)
+	}
+	result, err := serviceHistory(id)
+	if err != nil {
+		return api.Fail(err)
+	}
+	return api.JSON(http.StatusOK, result)
+}
+
+// serviceHistory returns the audit log of an animal, which outlives it once purged.
+func serviceHistory(id int) ([]*audit.Entry, error) {
+	es, err := audit.History("animal", id)
+	if err != nil {
+		return nil, api.Internal(err)
+	}
+	if len(es) == 0 {
+		if _, err := fetchAnimal(id, true); err != nil {
+			return nil, err
+		}
+	}
+	return es, nil
+}
@@ -0,0 +1,167 @@
+package animals
+
+import (
+	"math"
+	"os"
+	"sort"
+	"strconv"
+
+	"fazendadojuca.com.br/internal/api"
+)
+
+const (
+	inbreedingDepth            = 8
+	defaultInbreedingThreshold = 0.0625
+)
+
+var inbreedingThreshold = envFloat("INBREEDING_THRESHOLD", defaultInbreedingThreshold)
+
+type commonAncestor struct {
+	ID           int     `json:"id"`
+	Name         string  `json:"name"`
+	External     bool    `json:"external,omitempty"`
+	Inbreeding   float64 `json:"inbreeding"`
+	Contribution float64 `json:"contribution"`
+}
+
+type inbreeding struct {
+	Sire            int               `json:"sire"`
+	Dam             int               `json:"dam"`
+	Coefficient     float64           `json:"coefficient"`
+	Threshold       float64           `json:"threshold"`
+	Allowed         bool              `json:"allowed"`
+	CommonAncestors []*commonAncestor `json:"common_ancestors"`
+}
+
+func envFloat(name string, def float64) float64 {
+	f, err := strconv.ParseFloat(os.Getenv(name), 64)
+	if err != nil {
+		return def
+	}
+	return f
+}
+
+// pedigreeCalc computes Wright's coefficients over a set of already loaded animals,
+// keyed by parentKey so that calves of the same external sire count as relatives.
+type pedigreeCalc struct {
+	known map[int]*animal
+	memo  map[int]float64
+}
+
+func newPedigreeCalc(known map[int]*animal) *pedigreeCalc {
+	return &pedigreeCalc{known: known, memo: map[int]float64{}}
+}
+
+// paths lists every line of descent from id up to its ancestors, id included.
+func (c *pedigreeCalc) paths(id, depth int) [][]int {
+	var out [][]int
+	var walk func(path []int)
+	walk = func(path []int) {
+		cur := append([]int(nil), path...)
+		out = append(out, cur)
+		if len(path)-1 >= depth {
+			return
+		}
+		a := c.known[path[len(path)-1]]
+		if a == nil {
+			return
+		}
+		for _, pid := range []int{a.sire(), a.dam()} {
+			if pid != 0 && c.known[pid] != nil && !containsID(path, pid) {
+				walk(append(path, pid))
+			}
+		}
+	}
+	if c.known[id] != nil {
+		walk([]int{id})
+	}
+	return out
+}
+
+// individual returns the inbreeding coefficient of animal id itself.
+func (c *pedigreeCalc) individual(id int) float64 {
+	if f, ok := c.memo[id]; ok {
+		return f
+	}
+	c.memo[id] = 0
+	a := c.known[id]
+	if a == nil || a.sire() == 0 || a.dam() == 0 {
+		return 0
+	}
+	f, _ := c.offspring(a.sire(), a.dam())
+	c.memo[id] = f
+	return f
+}
+
+// offspring returns the inbreeding coefficient of a calf of sire and dam,
+// F = sum((1/2)^(n1+n2+1) * (1+Fa)), and the common ancestors contributing to it.
+func (c *pedigreeCalc) offspring(sire, dam int) (float64, []*commonAncestor) {
+	byAncestor := map[int]*commonAncestor{}
+	damPaths := c.paths(dam, inbreedingDepth)
+	for _, p1 := range c.paths(sire, inbreedingDepth) {
+		a := p1[len(p1)-1]
+		for _, p2 := range damPaths {
+			if p2[len(p2)-1] != a || !disjoint(p1[:len(p1)-1], p2[:len(p2)-1]) {
+				continue
+			}
+			ca, ok := byAncestor[a]
+			if !ok {
+				ca = &commonAncestor{ID: keyID(a), Name: c.known[a].Name, External: isExternal(a), Inbreeding: c.individual(a)}
+				byAncestor[a] = ca
+			}
+			n := len(p1) - 1 + len(p2) - 1
+			ca.Contribution += math.Pow(0.5, float64(n+1)) * (1 + ca.Inbreeding)
+		}
+	}
+	f := 0.0
+	cas := []*commonAncestor{}
+	for _, ca := range byAncestor {
+		f += ca.Contribution
+		cas = append(cas, ca)
+	}
+	sort.Slice(cas, func(i, j int) bool { return cas[i].Contribution > cas[j].Contribution })
+	return f, cas
+}
+
+func containsID(ids []int, id int) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+func disjoint(a, b []int) bool {
+	for _, id := range a {
+		if containsID(b, id) {
+			return false
+		}
+	}
+	return true
+}
+
+func serviceInbreeding(sire, dam int, includeDeleted bool) (*inbreeding, error) {
+	if sire == dam {
+		return nil, api.Validation("Sire And Dam Must Differ")
+	}
+	known, err := fetchAncestors([]int{sire, dam}, inbreedingDepth)
+	if err != nil {
+		return nil, err
+	}
+	if known[sire] == nil || known[sire].DeletedAt != nil && !includeDeleted {
+		return nil, api.NotFound("Sire Not Found")
+	}
+	if known[dam] == nil || known[dam].DeletedAt != nil && !includeDeleted {
+		return nil, api.NotFound("Dam Not Found")
+	}
+	f, cas := newPedigreeCalc(known).offspring(sire, dam)
+	return &inbreeding{
+		Sire:            sire,
+		Dam:             dam,
+		Coefficient:     f,
+		Threshold:       inbreedingThreshold,
+		Allowed:         f <= inbreedingThreshold,
+		CommonAncestors: cas,
+	}, nil
+}
@@ -0,0 +1,116 @@
+package animals
+
+import (
+	"math"
+	"testing"
+)
+
+func ptr(i int) *int { return &i }
+
+// herd builds the animals of a pedigree from their sire and dam keys, 0 when
+// unknown and negative for an external parent. Every external parent used must
+// be listed too, with no parents of its own.
+func herd(parents map[int][2]int) map[int]*animal {
+	known := map[int]*animal{}
+	for id, p := range parents {
+		a := &animal{ID: id}
+		for i, k := range p {
+			father, external := &a.Father, &a.ExternalFather
+			if i == 1 {
+				father, external = &a.Mother, &a.ExternalMother
+			}
+			switch {
+			case k > 0:
+				*father = ptr(k)
+			case k < 0:
+				*external = ptr(-k)
+			}
+		}
+		known[id] = a
+	}
+	return known
+}
+
+func TestOffspringInbreeding(t *testing.T) {
+	tests := []struct {
+		name      string
+		parents   map[int][2]int
+		sire, dam int
+		want      float64
+		ancestors []int
+	}{
+		{
+			name:    "unrelated",
+			parents: map[int][2]int{1: {}, 2: {}},
+			sire:    1, dam: 2,
+			want: 0,
+		},
+		{
+			name:    "sire by own daughter",
+			parents: map[int][2]int{1: {}, 2: {}, 3: {1, 2}},
+			sire:    1, dam: 3,
+			want:      0.25,
+			ancestors: []int{1},
+		},
+		{
+			name:    "half siblings",
+			parents: map[int][2]int{1: {}, 2: {}, 3: {}, 4: {1, 2}, 5: {1, 3}},
+			sire:    4, dam: 5,
+			want:      0.125,
+			ancestors: []int{1},
+		},
+		{
+			name:    "full siblings",
+			parents: map[int][2]int{1: {}, 2: {}, 4: {1, 2}, 5: {1, 2}},
+			sire:    4, dam: 5,
+			want:      0.25,
+			ancestors: []int{1, 2},
+		},
+		{
+			// 4 is a sire by own daughter (F = 1/4), so its calves 5 and 7 are half
+			// siblings through an inbred ancestor: (1/2)^3 * (1 + 1/4). The lines
+			// through 1 and 3 both pass through 4 and are not disjoint.
+			name:    "half siblings by an inbred sire",
+			parents: map[int][2]int{1: {}, 2: {}, 3: {1, 2}, 4: {1, 3}, 6: {}, 8: {}, 5: {4, 6}, 7: {4, 8}},
+			sire:    5, dam: 7,
+			want:      0.15625,
+			ancestors: []int{4},
+		},
+		{
+			name:    "half siblings by an external sire",
+			parents: map[int][2]int{-1: {}, 2: {}, 3: {}, 4: {-1, 2}, 5: {-1, 3}},
+			sire:    4, dam: 5,
+			want:      0.125,
+			ancestors: []int{-1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, cas := newPedigreeCalc(herd(tt.parents)).offspring(tt.sire, tt.dam)
+			if math.Abs(f-tt.want) > 1e-9 {
+				t.Errorf("F = %v, want %v", f, tt.want)
+			}
+			if len(cas) != len(tt.ancestors) {
+				t.Fatalf("%d common ancestors, want %v", len(cas), tt.ancestors)
+			}
+			for _, k := range tt.ancestors {
+				found := false
+				for _, ca := range cas {
+					found = found || ca.ID == keyID(k) && ca.External == isExternal(k)
+				}
+				if !found {
+					t.Errorf("common ancestor %d missing", k)
+				}
+			}
+		})
+	}
+}
+
+func TestIndividualInbreeding(t *testing.T) {
+	c := newPedigreeCalc(herd(map[int][2]int{1: {}, 2: {}, 3: {1, 2}, 4: {1, 3}, 5: {4, 2}}))
+	for id, want := range map[int]float64{1: 0, 3: 0, 4: 0.25, 5: 0.125} {
+		if f := c.individual(id); math.Abs(f-want) > 1e-9 {
+			t.Errorf("F(%d) = %v, want %v", id, f, want)
+		}
+	}
+}
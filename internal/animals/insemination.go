@@ -0,0 +1,67 @@
+package animals
+
+import (
+	"database/sql"
+
+	"fazendadojuca.com.br/internal/api"
+	"fazendadojuca.com.br/internal/audit"
+	"fazendadojuca.com.br/internal/database"
+	"fazendadojuca.com.br/internal/insemination"
+	"fazendadojuca.com.br/internal/validation"
+)
+
+// linkInsemination fills the parents of a calf from the insemination service it
+// came from and rejects parents that contradict it.
+func linkInsemination(a *animal, errs *validation.Errors) error {
+	if a.InseminationID == nil {
+		return nil
+	}
+	var cow int
+	var bull, externalBull *int
+	db := database.DB()
+	row := db.QueryRow("SELECT cow, bull, external_bull FROM insemination WHERE id = ?", *a.InseminationID)
+	err := row.Scan(&cow, &bull, &externalBull)
+	if err == sql.ErrNoRows {
+		errs.Add("insemination_id", validation.NotFound)
+		return nil
+	}
+	if err != nil {
+		return api.Internal(err)
+	}
+	if a.Mother == nil && a.ExternalMother == nil {
+		a.Mother = &cow
+	} else if a.Mother == nil || *a.Mother != cow {
+		errs.Add("mother", validation.InseminationMismatch)
+	}
+	if a.Father == nil && a.ExternalFather == nil {
+		a.Father, a.ExternalFather = bull, externalBull
+	} else if !sameID(a.Father, bull) || !sameID(a.ExternalFather, externalBull) {
+		errs.Add("father", validation.InseminationMismatch)
+	}
+	a.Insemination = 1
+	return nil
+}
+
+func sameID(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// unmarkCalved releases the insemination service a calf was linked to before
+// an update moved it to another service or to none.
+func unmarkCalved(tx database.Querier, before, a *animal, actor audit.Actor) error {
+	if before.InseminationID == nil || sameID(before.InseminationID, a.InseminationID) {
+		return nil
+	}
+	return insemination.UnmarkCalved(tx, *before.InseminationID, actor)
+}
+
+// markCalved records within tx that the insemination service of a calf ended in calving.
+func markCalved(tx database.Querier, a *animal, actor audit.Actor) error {
+	if a.InseminationID == nil {
+		return nil
+	}
+	return insemination.MarkCalved(tx, *a.InseminationID, actor)
+}
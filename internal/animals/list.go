@@ -0,0 +1,200 @@
+package animals
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"fazendadojuca.com.br/internal/api"
+	"fazendadojuca.com.br/internal/database"
+	"fazendadojuca.com.br/internal/date"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 500
+)
+
+var sortColumns = map[string]string{
+	"":       "a.id",
+	"birth":  "a.birth",
+	"name":   "a.name",
+	"number": "a.number",
+}
+
+// animalPage is the envelope of GET /animals.
+type animalPage struct {
+	Items      []*animal `json:"items"`
+	Total      int       `json:"total"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
+
+// cursor points after the last animal of a page: its sort value and id.
+type cursor struct {
+	Value string `json:"v,omitempty"`
+	ID    int    `json:"id"`
+}
+
+type listQuery struct {
+	limit   int
+	after   *cursor
+	sort    string
+	desc    bool
+	filters []string
+	args    []interface{}
+}
+
+func encodeCursor(c cursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (*cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, api.Validation("Invalid Cursor")
+	}
+	c := new(cursor)
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, api.Validation("Invalid Cursor")
+	}
+	return c, nil
+}
+
+func (q *listQuery) filter(cond string, args ...interface{}) {
+	q.filters = append(q.filters, cond)
+	q.args = append(q.args, args...)
+}
+
+func parseListQuery(req api.Request) (*listQuery, error) {
+	params := req.QueryStringParameters
+	limit, err := api.QueryInt(req, "limit", defaultListLimit, maxListLimit)
+	if err != nil {
+		return nil, err
+	}
+	q := &listQuery{limit: limit, sort: params["sort"], desc: params["order"] == "desc"}
+	if _, ok := sortColumns[q.sort]; !ok {
+		return nil, api.Validation("Invalid Sort")
+	}
+	if o := params["order"]; o != "" && o != "asc" && o != "desc" {
+		return nil, api.Validation("Invalid Order")
+	}
+	if c := params["cursor"]; c != "" {
+		if q.after, err = decodeCursor(c); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range []struct{ param, column string }{
+		{"gender_id", "a.gender_id"},
+		{"breed_id", "a.breed_id"},
+		{"purity_level_id", "a.purity_level_id"},
+	} {
+		v := params[f.param]
+		if v == "" {
+			continue
+		}
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, api.Validation("Invalid " + f.param)
+		}
+		q.filter(f.column+" = ?", id)
+	}
+	if v := params["alive"]; v != "" {
+		alive, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, api.Validation("Invalid Alive")
+		}
+		cond := "(a.death IS NULL OR a.death > ?)"
+		if !alive {
+			cond = "NOT " + cond
+		}
+		q.filter(cond, date.Today())
+	}
+	for _, f := range []struct{ param, cond string }{
+		{"born_after", "a.birth >= ?"},
+		{"born_before", "a.birth <= ?"},
+	} {
+		v := params[f.param]
+		if v == "" {
+			continue
+		}
+		d, err := date.Parse(v)
+		if err != nil {
+			return nil, api.Validation("Invalid " + f.param)
+		}
+		q.filter(f.cond, d)
+	}
+	deleted, err := includeDeleted(req)
+	if err != nil {
+		return nil, err
+	}
+	if !deleted {
+		q.filter("a.deleted_at IS NULL")
+	}
+	if v := strings.TrimSpace(params["q"]); v != "" {
+		like := "%" + likeEscaper.Replace(v) + "%"
+		q.filter("(a.name LIKE ? ESCAPE '!' OR a.number LIKE ? ESCAPE '!' OR a.registry LIKE ? ESCAPE '!')", like, like, like)
+	}
+	return q, nil
+}
+
+// likeEscaper makes the LIKE wildcards in a search match themselves. The escape
+// character is ! rather than a backslash, which MySQL and SQLite quote differently.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+func (q *listQuery) where(conds []string) string {
+	if len(conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conds, " AND ")
+}
+
+func (q *listQuery) sortValue(a *animal) string {
+	switch q.sort {
+	case "birth":
+		return a.Birth.String()
+	case "name":
+		return a.Name
+	case "number":
+		return a.Number
+	}
+	return ""
+}
+
+func serviceList(q *listQuery) (*animalPage, error) {
+	page := &animalPage{}
+	row := database.DB().QueryRow("SELECT COUNT(*) FROM animal a"+q.where(q.filters), q.args...)
+	if err := row.Scan(&page.Total); err != nil {
+		return nil, api.Internal(err)
+	}
+
+	col, dir, cmp := sortColumns[q.sort], "ASC", ">"
+	if q.desc {
+		dir, cmp = "DESC", "<"
+	}
+	conds, args := q.filters, q.args
+	if q.after != nil {
+		conds = append(conds[:len(conds):len(conds)], "a.id "+cmp+" ?")
+		args = append(args[:len(args):len(args)], q.after.ID)
+		if q.sort != "" {
+			conds[len(conds)-1] = "(" + col + " " + cmp + " ? OR (" + col + " = ? AND a.id " + cmp + " ?))"
+			args = append(args[:len(args)-1], q.after.Value, q.after.Value, q.after.ID)
+		}
+	}
+	order := " ORDER BY " + col + " " + dir
+	if q.sort != "" {
+		order += ", a.id " + dir
+	}
+	as, err := queryAnimals(animalSelect+q.where(conds)+order+" LIMIT ?", append(args, q.limit+1)...)
+	if err != nil {
+		return nil, err
+	}
+	if len(as) > q.limit {
+		as = as[:q.limit]
+		last := as[len(as)-1]
+		page.NextCursor = encodeCursor(cursor{Value: q.sortValue(last), ID: last.ID})
+	}
+	page.Items = as
+	return page, nil
+}
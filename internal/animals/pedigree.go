@@ -0,0 +1,130 @@
+package animals
+
+import (
+	"strings"
+
+	"fazendadojuca.com.br/internal/api"
+)
+
+const (
+	defaultPedigreeDepth = 3
+	maxPedigreeDepth     = 10
+)
+
+type pedigreeNode struct {
+	ID          int           `json:"id"`
+	Name        string        `json:"name"`
+	Gender      gender        `json:"gender"`
+	Breed       breed         `json:"breed"`
+	PurityLevel purityLevel   `json:"purity_level"`
+	Sire        *pedigreeNode `json:"sire,omitempty"`
+	Dam         *pedigreeNode `json:"dam,omitempty"`
+	External    bool          `json:"external,omitempty"`
+	Cycle       bool          `json:"cycle,omitempty"`
+}
+
+// serviceFetchMany loads the given animals with a single query, keyed by ID.
+func serviceFetchMany(ids []int) (map[int]*animal, error) {
+	as := map[int]*animal{}
+	if len(ids) == 0 {
+		return as, nil
+	}
+	args := intArgs(ids)
+	found, err := queryAnimals(animalSelect+" WHERE a.id IN ("+placeholders(len(ids))+")", args...)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range found {
+		as[a.ID] = a
+	}
+	return as, nil
+}
+
+func intArgs(ids []int) []interface{} {
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return args
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// fetchAncestors loads ids and their ancestors up to depth generations, one query per generation,
+// keyed by parentKey. Animals that do not exist are left out of the result.
+func fetchAncestors(ids []int, depth int) (map[int]*animal, error) {
+	known, err := serviceFetchMany(ids)
+	if err != nil {
+		return nil, err
+	}
+	generation := []int{}
+	for _, id := range ids {
+		if known[id] != nil {
+			generation = append(generation, id)
+		}
+	}
+	for d := 0; d < depth && len(generation) > 0; d++ {
+		parents := []int{}
+		for _, cid := range generation {
+			a := known[cid]
+			for _, pid := range []int{a.sire(), a.dam()} {
+				if _, ok := known[pid]; !ok && pid != 0 {
+					known[pid] = nil
+					parents = append(parents, pid)
+				}
+			}
+		}
+		found, err := fetchParents(parents...)
+		if err != nil {
+			return nil, err
+		}
+		generation = generation[:0]
+		for _, pid := range parents {
+			known[pid] = found[pid]
+			if found[pid] != nil {
+				generation = append(generation, pid)
+			}
+		}
+	}
+	return known, nil
+}
+
+func buildPedigree(known map[int]*animal, id, depth int, path map[int]bool) *pedigreeNode {
+	a := known[id]
+	if a == nil {
+		return nil
+	}
+	n := &pedigreeNode{
+		ID:          keyID(id),
+		Name:        a.Name,
+		External:    isExternal(id),
+		Gender:      a.Gender,
+		Breed:       a.Breed,
+		PurityLevel: a.PurityLevel,
+	}
+	if path[id] {
+		n.Cycle = true
+		return n
+	}
+	if depth == 0 {
+		return n
+	}
+	path[id] = true
+	n.Sire = buildPedigree(known, a.sire(), depth-1, path)
+	n.Dam = buildPedigree(known, a.dam(), depth-1, path)
+	path[id] = false
+	return n
+}
+
+func servicePedigree(id, depth int, includeDeleted bool) (*pedigreeNode, error) {
+	known, err := fetchAncestors([]int{id}, depth)
+	if err != nil {
+		return nil, err
+	}
+	if known[id] == nil || known[id].DeletedAt != nil && !includeDeleted {
+		return nil, api.NotFound("Animal Not Found")
+	}
+	return buildPedigree(known, id, depth, map[int]bool{}), nil
+}
@@ -0,0 +1,138 @@
+package animals
+
+import (
+	"math/big"
+	"sort"
+
+	"fazendadojuca.com.br/internal/api"
+	"fazendadojuca.com.br/internal/date"
+)
+
+const maxPlannerGenerations = 10
+
+type sireSuggestion struct {
+	Sire        *animal      `json:"sire"`
+	CalfPurity  string       `json:"calf_purity"`
+	PurityLevel *purityLevel `json:"purity_level,omitempty"`
+	// Generations is nil when the target is not reached within maxPlannerGenerations.
+	Generations *int `json:"generations"`
+}
+
+// rank orders suggestions by generations, those never reaching the target last.
+func (s *sireSuggestion) rank() int {
+	if s.Generations == nil {
+		return maxPlannerGenerations + 1
+	}
+	return *s.Generations
+}
+
+type matingPlan struct {
+	Dam    int               `json:"dam"`
+	Breed  breed             `json:"breed"`
+	Target purityLevel       `json:"target"`
+	Sires  []*sireSuggestion `json:"sires"`
+}
+
+// isAlive reports whether a has no death date, or one still to come.
+func isAlive(a *animal) bool {
+	return a.Death == nil || a.Death.After(date.Today().Time)
+}
+
+func serviceFetchMales() ([]*animal, error) {
+	return queryAnimals(animalSelect+" WHERE g.sex = ? AND a.deleted_at IS NULL", sexMale)
+}
+
+// generationsTo counts the generations needed for a calf of purity p to reach target,
+// assuming every later generation is bred to a pure sire. It returns nil when
+// target is not reached within maxPlannerGenerations, as for a pure target,
+// which grading up only approaches.
+func generationsTo(p, target *big.Rat) *int {
+	cur := new(big.Rat).Set(p)
+	half := big.NewRat(1, 2)
+	for g := 1; g <= maxPlannerGenerations; g++ {
+		if cur.Cmp(target) >= 0 {
+			return &g
+		}
+		cur.Add(cur, big.NewRat(1, 1))
+		cur.Mul(cur, half)
+	}
+	return nil
+}
+
+func servicePlanner(damID, targetID, breedID int) (*matingPlan, error) {
+	dam, err := serviceFetchOne(damID)
+	if err != nil {
+		return nil, err
+	}
+	if !dam.Gender.is(sexFemale) {
+		return nil, api.Validation("Dam Must Be Female")
+	}
+	if breedID == 0 {
+		breedID = dam.Breed.ID
+	}
+	b, err := fetchBreed(breedID)
+	if err != nil {
+		return nil, err
+	}
+	levels, err := fetchPurityLevels()
+	if err != nil {
+		return nil, err
+	}
+	var target *purityLevel
+	for _, p := range levels {
+		if p.ID == targetID {
+			target = p
+		}
+	}
+	if target == nil {
+		return nil, api.Validation("Target Purity Level Not Found")
+	}
+	targetRat, err := parseFraction(target.Level)
+	if err != nil {
+		return nil, err
+	}
+	males, err := serviceFetchMales()
+	if err != nil {
+		return nil, err
+	}
+	ids := []int{dam.ID}
+	candidates := []*animal{}
+	for _, m := range males {
+		if isAlive(m) {
+			ids = append(ids, m.ID)
+			candidates = append(candidates, m)
+		}
+	}
+	known, err := fetchAncestors(ids, inbreedingDepth)
+	if err != nil {
+		return nil, err
+	}
+	calc := newPedigreeCalc(known)
+	plan := &matingPlan{Dam: dam.ID, Breed: *b, Target: *target, Sires: []*sireSuggestion{}}
+	purities := map[*sireSuggestion]*big.Rat{}
+	for _, m := range candidates {
+		if f, _ := calc.offspring(m.ID, dam.ID); f > 0 {
+			continue
+		}
+		r, err := calfPurity(*b, m, dam)
+		if err != nil {
+			continue
+		}
+		s := &sireSuggestion{
+			Sire:        m,
+			CalfPurity:  r.RatString(),
+			PurityLevel: matchPurityLevel(levels, r),
+			Generations: generationsTo(r, targetRat),
+		}
+		purities[s] = r
+		plan.Sires = append(plan.Sires, s)
+	}
+	sort.SliceStable(plan.Sires, func(i, j int) bool {
+		si, sj := plan.Sires[i], plan.Sires[j]
+		if gi, gj := si.rank(), sj.rank(); gi != gj {
+			return gi < gj
+		}
+		return purities[si].Cmp(purities[sj]) > 0
+	})
+	return plan, nil
+}
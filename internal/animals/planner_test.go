@@ -0,0 +1,30 @@
+package animals
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestGenerationsTo(t *testing.T) {
+	tests := []struct {
+		p, target *big.Rat
+		want      int // 0 when never reached
+	}{
+		{big.NewRat(1, 2), big.NewRat(1, 2), 1},
+		{big.NewRat(1, 2), big.NewRat(3, 4), 2},
+		{big.NewRat(1, 2), big.NewRat(31, 32), 5},
+		{big.NewRat(0, 1), big.NewRat(15, 16), 5},
+		{big.NewRat(1, 1), big.NewRat(1, 1), 1},
+		{big.NewRat(1, 2), big.NewRat(1, 1), 0},
+		{big.NewRat(1, 2), big.NewRat(2047, 2048), 0},
+	}
+	for _, tt := range tests {
+		got := generationsTo(tt.p, tt.target)
+		switch {
+		case tt.want == 0 && got != nil:
+			t.Errorf("generationsTo(%s, %s) = %d, want nil", tt.p.RatString(), tt.target.RatString(), *got)
+		case tt.want != 0 && (got == nil || *got != tt.want):
+			t.Errorf("generationsTo(%s, %s) = %v, want %d", tt.p.RatString(), tt.target.RatString(), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package animals
+
+const (
+	defaultProgenyGenerations = 1
+	maxProgenyGenerations     = 10
+)
+
+type offspring struct {
+	*animal
+	Generation int `json:"generation"`
+}
+
+type progenySummary struct {
+	Total       int            `json:"total"`
+	Gender      map[string]int `json:"gender"`
+	Breed       map[string]int `json:"breed"`
+	PurityLevel map[string]int `json:"purity_level"`
+}
+
+type progeny struct {
+	ID        int            `json:"id"`
+	Offspring []*offspring   `json:"offspring"`
+	Summary   progenySummary `json:"summary"`
+}
+
+// serviceFetchChildren loads every animal whose father or mother is one of ids,
+// deleted ones included.
+func serviceFetchChildren(ids []int) ([]*animal, error) {
+	if len(ids) == 0 {
+		return []*animal{}, nil
+	}
+	args := intArgs(ids)
+	args = append(args, args...)
+	in := "(" + placeholders(len(ids)) + ")"
+	return queryAnimals(animalSelect+" WHERE a.father IN "+in+" OR a.mother IN "+in, args...)
+}
+
+// serviceProgeny lists the descendants of id. Deleted ones are only listed when
+// includeDeleted is set, but their own offspring are always followed.
+func serviceProgeny(id, generations int, includeDeleted bool) (*progeny, error) {
+	_, err := fetchAnimal(id, includeDeleted)
+	if err != nil {
+		return nil, err
+	}
+	p := &progeny{
+		ID:        id,
+		Offspring: []*offspring{},
+		Summary: progenySummary{
+			Gender:      map[string]int{},
+			Breed:       map[string]int{},
+			PurityLevel: map[string]int{},
+		},
+	}
+	seen := map[int]bool{id: true}
+	generation := []int{id}
+	for g := 1; g <= generations && len(generation) > 0; g++ {
+		children, err := serviceFetchChildren(generation)
+		if err != nil {
+			return nil, err
+		}
+		generation = []int{}
+		for _, c := range children {
+			if seen[c.ID] {
+				continue
+			}
+			seen[c.ID] = true
+			generation = append(generation, c.ID)
+			if c.DeletedAt != nil && !includeDeleted {
+				continue
+			}
+			p.Offspring = append(p.Offspring, &offspring{c, g})
+			p.Summary.Total++
+			p.Summary.Gender[c.Gender.Name]++
+			p.Summary.Breed[c.Breed.Name]++
+			p.Summary.PurityLevel[c.PurityLevel.Level]++
+		}
+	}
+	return p, nil
+}
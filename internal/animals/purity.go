@@ -0,0 +1,206 @@
+package animals
+
+import (
+	"database/sql"
+	"fmt"
+	"math/big"
+
+	"fazendadojuca.com.br/internal/api"
+	"fazendadojuca.com.br/internal/database"
+	"fazendadojuca.com.br/internal/validation"
+)
+
+// compatibleBreed reports whether a parent of breed p passes its blood on to a calf of
+// breed c: neither breed is unknown, and they are the same breed or of the same group.
+func compatibleBreed(c, p breed) bool {
+	if c.Unknown || p.Unknown {
+		return false
+	}
+	return c.ID == p.ID || c.Group != nil && p.Group != nil && *c.Group == *p.Group
+}
+
+func parseFraction(level string) (*big.Rat, error) {
+	r, ok := validation.Fraction(level)
+	if !ok {
+		return nil, api.Validation(fmt.Sprintf("Invalid Purity Level %q", level))
+	}
+	return r, nil
+}
+
+func parentPurity(c breed, p *animal) (*big.Rat, error) {
+	if !compatibleBreed(c, p.Breed) {
+		return new(big.Rat), nil
+	}
+	return parseFraction(p.PurityLevel.Level)
+}
+
+func calfPurity(c breed, father, mother *animal) (*big.Rat, error) {
+	f, err := parentPurity(c, father)
+	if err != nil {
+		return nil, err
+	}
+	m, err := parentPurity(c, mother)
+	if err != nil {
+		return nil, err
+	}
+	r := new(big.Rat).Add(f, m)
+	return r.Quo(r, big.NewRat(2, 1)), nil
+}
+
+func matchPurityLevel(levels []*purityLevel, r *big.Rat) *purityLevel {
+	for _, p := range levels {
+		pr, err := parseFraction(p.Level)
+		if err == nil && pr.Cmp(r) == 0 {
+			return p
+		}
+	}
+	return nil
+}
+
+// floorPurityLevel returns the highest level below r, or the lowest level when
+// they are all above it.
+func floorPurityLevel(levels []*purityLevel, r *big.Rat) *purityLevel {
+	var below, lowest *purityLevel
+	var belowR, lowestR *big.Rat
+	for _, p := range levels {
+		pr, err := parseFraction(p.Level)
+		if err != nil {
+			continue
+		}
+		if pr.Cmp(r) <= 0 && (below == nil || pr.Cmp(belowR) > 0) {
+			below, belowR = p, pr
+		}
+		if lowest == nil || pr.Cmp(lowestR) < 0 {
+			lowest, lowestR = p, pr
+		}
+	}
+	if below != nil {
+		return below
+	}
+	return lowest
+}
+
+// derivePurity picks the purity level of a calf of breed c out of levels. The
+// level matching the purity computed from its parents wins, and a different
+// sent one is rejected. When no level matches, or the breed is unknown, the
+// sent level is kept; without one, a calf of a known breed gets the highest
+// level below its purity.
+func derivePurity(c breed, father, mother *animal, levels []*purityLevel, sent purityLevel) (*purityLevel, error) {
+	required := api.Invalid([]api.FieldError{{Field: "purity_level", Code: validation.Required}})
+	if c.Unknown {
+		if sent.ID == 0 {
+			return nil, required
+		}
+		return &sent, nil
+	}
+	r, err := calfPurity(c, father, mother)
+	if err != nil {
+		return nil, err
+	}
+	if p := matchPurityLevel(levels, r); p != nil {
+		if sent.ID != 0 && sent.ID != p.ID {
+			return nil, api.Validation(fmt.Sprintf("Purity Level Mismatch, Expected %s", p.Level))
+		}
+		return p, nil
+	}
+	if sent.ID != 0 {
+		return &sent, nil
+	}
+	if p := floorPurityLevel(levels, r); p != nil {
+		return p, nil
+	}
+	return nil, required
+}
+
+// resolvePurity derives the purity level of a from its parents. Animals of unknown origin
+// (no father or mother) keep the purity level sent by the client.
+func resolvePurity(a *animal) error {
+	if a.sire() == 0 || a.dam() == 0 {
+		if a.PurityLevel.ID == 0 {
+			return api.Invalid([]api.FieldError{{Field: "purity_level", Code: validation.Required}})
+		}
+		return nil
+	}
+	parents, err := fetchParents(a.sire(), a.dam())
+	if err != nil {
+		return err
+	}
+	father, mother := parents[a.sire()], parents[a.dam()]
+	if father == nil {
+		return api.Validation("Father Not Found")
+	}
+	if mother == nil {
+		return api.Validation("Mother Not Found")
+	}
+	b, err := fetchBreed(a.Breed.ID)
+	if err != nil {
+		return err
+	}
+	levels, err := fetchPurityLevels()
+	if err != nil {
+		return err
+	}
+	p, err := derivePurity(*b, father, mother, levels, a.PurityLevel)
+	if err != nil {
+		return err
+	}
+	a.PurityLevel = *p
+	return nil
+}
+
+// fetchParents loads the herd or external parents behind the given keys, keyed by parentKey.
+func fetchParents(keys ...int) (map[int]*animal, error) {
+	herd, external := []int{}, []int{}
+	for _, k := range keys {
+		if isExternal(k) {
+			external = append(external, keyID(k))
+		} else {
+			herd = append(herd, k)
+		}
+	}
+	parents, err := serviceFetchMany(herd)
+	if err != nil {
+		return nil, err
+	}
+	externals, err := fetchExternalParents(external)
+	if err != nil {
+		return nil, err
+	}
+	for k, p := range externals {
+		parents[k] = p
+	}
+	return parents, nil
+}
+
+func fetchBreed(id int) (*breed, error) {
+	db := database.DB()
+	b := new(breed)
+	row := db.QueryRow("SELECT id, name, breed_group, unknown FROM breed WHERE id= ?", id)
+	err := row.Scan(&b.ID, &b.Name, &b.Group, &b.Unknown)
+	if err == sql.ErrNoRows {
+		return nil, api.Validation("Breed Not Found")
+	}
+	if err != nil {
+		return nil, api.Internal(err)
+	}
+	return b, nil
+}
+
+func fetchPurityLevels() ([]*purityLevel, error) {
+	db := database.DB()
+	results, err := db.Query("SELECT id, level FROM purity_level")
+	if err != nil {
+		return nil, api.Internal(err)
+	}
+	defer results.Close()
+	ps := []*purityLevel{}
+	for results.Next() {
+		var p = new(purityLevel)
+		err = results.Scan(&p.ID, &p.Level)
+		if err != nil {
+			return nil, api.Internal(err)
+		}
+		ps = append(ps, p)
+	}
+	return ps, nil
+}
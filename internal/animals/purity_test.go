@@ -0,0 +1,117 @@
+package animals
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestParseFraction(t *testing.T) {
+	tests := []struct {
+		level string
+		want  *big.Rat
+	}{
+		{"1", big.NewRat(1, 1)},
+		{"1/2", big.NewRat(1, 2)},
+		{" 15/16 ", big.NewRat(15, 16)},
+		{"0", big.NewRat(0, 1)},
+	}
+	for _, tt := range tests {
+		got, err := parseFraction(tt.level)
+		if err != nil {
+			t.Errorf("parseFraction(%q): %v", tt.level, err)
+			continue
+		}
+		if got.Cmp(tt.want) != 0 {
+			t.Errorf("parseFraction(%q) = %s, want %s", tt.level, got.RatString(), tt.want.RatString())
+		}
+	}
+	for _, level := range []string{"", "PO", "3/2", "-1/2", "1/0"} {
+		if _, err := parseFraction(level); err == nil {
+			t.Errorf("parseFraction(%q) succeeded, want an error", level)
+		}
+	}
+}
+
+func TestCalfPurity(t *testing.T) {
+	angus := "Angus"
+	aberdeen := breed{ID: 2, Group: &angus}
+	american := breed{ID: 3, Group: &angus}
+	nelore := breed{ID: 6}
+	unknown := breed{ID: 1, Unknown: true}
+	parent := func(b breed, level string) *animal {
+		return &animal{Breed: b, PurityLevel: purityLevel{Level: level}}
+	}
+	tests := []struct {
+		name           string
+		calf           breed
+		father, mother *animal
+		want           *big.Rat
+	}{
+		{"pure parents", aberdeen, parent(aberdeen, "1"), parent(aberdeen, "1"), big.NewRat(1, 1)},
+		{"grading up", aberdeen, parent(aberdeen, "1"), parent(aberdeen, "1/2"), big.NewRat(3, 4)},
+		{"same breed group", aberdeen, parent(american, "1"), parent(aberdeen, "1/2"), big.NewRat(3, 4)},
+		{"breed without a group", nelore, parent(nelore, "1"), parent(nelore, "7/8"), big.NewRat(15, 16)},
+		{"other breed", aberdeen, parent(aberdeen, "1"), parent(nelore, "1"), big.NewRat(1, 2)},
+		{"unknown parent breed", aberdeen, parent(aberdeen, "1"), parent(unknown, "1"), big.NewRat(1, 2)},
+	}
+	for _, tt := range tests {
+		got, err := calfPurity(tt.calf, tt.father, tt.mother)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got.Cmp(tt.want) != 0 {
+			t.Errorf("%s: purity %s, want %s", tt.name, got.RatString(), tt.want.RatString())
+		}
+	}
+}
+
+func TestDerivePurity(t *testing.T) {
+	angus := "Angus"
+	aberdeen := breed{ID: 2, Group: &angus}
+	nelore := breed{ID: 6}
+	unknown := breed{ID: 1, Unknown: true}
+	levels := []*purityLevel{
+		{ID: 1, Level: "1"},
+		{ID: 2, Level: "1/2"},
+		{ID: 3, Level: "3/4"},
+		{ID: 4, Level: "7/8"},
+		{ID: 5, Level: "15/16"},
+		{ID: 6, Level: "31/32"},
+	}
+	parent := func(b breed, level string) *animal {
+		return &animal{Breed: b, PurityLevel: purityLevel{Level: level}}
+	}
+	tests := []struct {
+		name           string
+		calf           breed
+		father, mother *animal
+		sent           int
+		want           int
+	}{
+		{"matching level", aberdeen, parent(aberdeen, "1"), parent(aberdeen, "1/2"), 0, 3},
+		{"matching level sent", aberdeen, parent(aberdeen, "1"), parent(aberdeen, "1/2"), 3, 3},
+		{"63/64 derived", aberdeen, parent(aberdeen, "1"), parent(aberdeen, "31/32"), 0, 6},
+		{"63/64 sent", aberdeen, parent(aberdeen, "1"), parent(aberdeen, "31/32"), 1, 1},
+		{"1/4 derived", aberdeen, parent(aberdeen, "1/2"), parent(nelore, "1"), 0, 2},
+		{"1/4 sent", aberdeen, parent(aberdeen, "1/2"), parent(nelore, "1"), 2, 2},
+		{"unknown calf breed", unknown, parent(unknown, "1"), parent(unknown, "1"), 4, 4},
+	}
+	for _, tt := range tests {
+		got, err := derivePurity(tt.calf, tt.father, tt.mother, levels, purityLevel{ID: tt.sent})
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got.ID != tt.want {
+			t.Errorf("%s: purity level %d, want %d", tt.name, got.ID, tt.want)
+		}
+	}
+
+	if _, err := derivePurity(aberdeen, parent(aberdeen, "1"), parent(aberdeen, "1/2"), levels, purityLevel{ID: 1}); err == nil {
+		t.Errorf("mismatching level sent: succeeded, want an error")
+	}
+	if _, err := derivePurity(unknown, parent(unknown, "1"), parent(unknown, "1"), levels, purityLevel{}); err == nil {
+		t.Errorf("unknown calf breed without a level: succeeded, want an error")
+	}
+}
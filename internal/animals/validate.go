@@ -0,0 +1,145 @@
+package animals
+
+import (
+	"fmt"
+	"strings"
+
+	"fazendadojuca.com.br/internal/api"
+	"fazendadojuca.com.br/internal/database"
+	"fazendadojuca.com.br/internal/date"
+	"fazendadojuca.com.br/internal/validation"
+)
+
+// validateAnimal checks a create or update payload, reporting every invalid
+// field at once. A calf of an insemination gets its parents filled in first.
+func validateAnimal(a *animal) error {
+	errs := &validation.Errors{}
+	a.Name = strings.TrimSpace(a.Name)
+	errs.Check(a.Name != "", "name", validation.Required)
+	errs.MaxLen(a.Name, 45, "name")
+	errs.MaxLen(a.Number, 255, "number")
+	errs.MaxLen(a.Registry, 255, "registry")
+	errs.MaxLen(a.Origin, 255, "origin")
+
+	for _, ref := range []struct {
+		field, table string
+		id           int
+		required     bool
+	}{
+		{"gender", "gender", a.Gender.ID, true},
+		{"breed", "breed", a.Breed.ID, true},
+		{"purity_level", "purity_level", a.PurityLevel.ID, false},
+	} {
+		if ref.id == 0 {
+			errs.Check(!ref.required, ref.field, validation.Required)
+			continue
+		}
+		ok, err := exists(ref.table, ref.id)
+		if err != nil {
+			return err
+		}
+		errs.Check(ok, ref.field, validation.NotFound)
+	}
+
+	if a.Birth.IsZero() {
+		errs.Add("birth", validation.Required)
+	}
+	errs.Check(!a.Birth.After(date.Today().Time), "birth", validation.InFuture)
+	if a.Death != nil {
+		errs.Check(!a.Death.Before(a.Birth.Time), "death", validation.BeforeBirth)
+	}
+
+	if err := linkInsemination(a, errs); err != nil {
+		return err
+	}
+	if err := validateParents(a, errs); err != nil {
+		return err
+	}
+	return errs.Err()
+}
+
+// validateParents checks that each parent exists, has the right gender and was
+// born before a, and that no parent is both a herd and an external one. A
+// deleted animal counts as missing, unless an update keeps it as the parent it
+// already was.
+func validateParents(a *animal, errs *validation.Errors) error {
+	errs.Check(a.Father == nil || a.ExternalFather == nil, "external_father", validation.Conflict)
+	errs.Check(a.Mother == nil || a.ExternalMother == nil, "external_mother", validation.Conflict)
+	keys := []int{}
+	for _, k := range []int{a.sire(), a.dam()} {
+		if k != 0 {
+			keys = append(keys, k)
+		}
+	}
+	parents, err := fetchParents(keys...)
+	if err != nil {
+		return err
+	}
+	for _, p := range []struct {
+		field, sex string
+		key        int
+		of         func(*animal) int
+	}{
+		{parentField("father", a.sire()), sexMale, a.sire(), (*animal).sire},
+		{parentField("mother", a.dam()), sexFemale, a.dam(), (*animal).dam},
+	} {
+		if p.key == 0 {
+			continue
+		}
+		if a.ID != 0 && p.key == a.ID {
+			errs.Add(p.field, validation.SelfReference)
+			continue
+		}
+		parent := parents[p.key]
+		if parent != nil && parent.DeletedAt != nil {
+			kept, err := keptParent(a.ID, p.key, p.of)
+			if err != nil {
+				return err
+			}
+			if !kept {
+				parent = nil
+			}
+		}
+		if parent == nil {
+			errs.Add(p.field, validation.NotFound)
+			continue
+		}
+		if isExternal(p.key) {
+			continue
+		}
+		errs.Check(parent.Gender.is(p.sex), p.field, validation.WrongGender)
+		errs.Check(a.Birth.IsZero() || parent.Birth.Before(a.Birth.Time), p.field, validation.YoungerThanCalf)
+	}
+	return nil
+}
+
+// keptParent reports whether the stored animal id already has key as the
+// parent of returns.
+func keptParent(id, key int, of func(*animal) int) (bool, error) {
+	if id == 0 {
+		return false, nil
+	}
+	stored, err := fetchAnimal(id, true)
+	if err != nil {
+		return false, err
+	}
+	return of(stored) == key, nil
+}
+
+func parentField(name string, key int) string {
+	if isExternal(key) {
+		return "external_" + name
+	}
+	return name
+}
+
+// exists reports whether table has a row with id.
+func exists(table string, id int) (bool, error) {
+	var n int
+	db := database.DB()
+	err := db.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE id = ?", table), id).Scan(&n)
+	if err != nil {
+		return false, api.Internal(err)
+	}
+	return n > 0, nil
+}
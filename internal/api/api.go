@@ -0,0 +1,174 @@
+// Package api holds the API Gateway scaffold shared by every Lambda: the JSON
+// response helpers and a generic CRUD handler.
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+
+	"fazendadojuca.com.br/internal/audit"
+)
+
+// Request and Response are the API Gateway proxy events every handler works with.
+type (
+	Request  = events.APIGatewayProxyRequest
+	Response = events.APIGatewayProxyResponse
+)
+
+// HandlerFunc is the signature passed to lambda.Start.
+type HandlerFunc func(req Request) (*Response, error)
+
+type ErrorBody struct {
+	ErrorMsg *string      `json:"error,omitempty"`
+	Code     string       `json:"code,omitempty"`
+	Details  interface{}  `json:"details,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// Service is the set of operations the CRUD handler exposes for an entity.
+// Update and Delete only apply to the given version of a Versioned record, and
+// fail with PreconditionFailed once it changed. Version 0 matches any. Every
+// write is recorded in the audit log as made by actor.
+type Service[T any] interface {
+	FetchOne(id int) (*T, error)
+	FetchAll() ([]*T, error)
+	Create(v *T, actor audit.Actor) (*T, error)
+	Update(v *T, actor audit.Actor) (*T, error)
+	Delete(id, version int, actor audit.Actor) error
+}
+
+// Handler routes GET, POST, PUT, PATCH and DELETE requests to s.
+func Handler[T any](s Service[T]) HandlerFunc {
+	return Recover(func(req Request) (*Response, error) {
+		switch req.HTTPMethod {
+		case "GET":
+			return get(s, req)
+		case "POST":
+			return create(s, req)
+		case "PUT":
+			return update(s, req)
+		case "PATCH":
+			return patch(s, req)
+		case "DELETE":
+			return remove(s, req)
+		default:
+			return UnhandledMethod()
+		}
+	})
+}
+
+func JSON(status int, body interface{}) (*Response, error) {
+	resp := Response{Headers: map[string]string{"Content-Type": "application/json"}}
+	resp.StatusCode = status
+
+	stringBody, _ := json.Marshal(body)
+	resp.Body = string(stringBody)
+	return &resp, nil
+}
+
+func UnhandledMethod() (*Response, error) {
+	return JSON(http.StatusMethodNotAllowed, "method Not allowed")
+}
+
+// Decode unmarshals the request body into a new T.
+func Decode[T any](req Request) (*T, error) {
+	v := new(T)
+	err := json.Unmarshal([]byte(req.Body), v)
+	if err != nil {
+		return nil, Validation("Invalid Data")
+	}
+	return v, nil
+}
+
+// PathID parses the {id} path parameter.
+func PathID(req Request) (int, error) {
+	id, err := strconv.Atoi(req.PathParameters["id"])
+	if err != nil || id == 0 {
+		return 0, Validation("Invalid ID")
+	}
+	return id, nil
+}
+
+// QueryInt parses an optional query parameter in the range [1, max], defaulting to def.
+func QueryInt(req Request, name string, def, max int) (int, error) {
+	v, ok := req.QueryStringParameters[name]
+	if !ok || v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 || n > max {
+		return 0, Validation("Invalid " + strings.ToUpper(name[:1]) + name[1:])
+	}
+	return n, nil
+}
+
+func get[T any](s Service[T], req Request) (*Response, error) {
+	queryid := req.QueryStringParameters["id"]
+	id, err := strconv.Atoi(queryid)
+	if err == nil {
+		result, err := s.FetchOne(id)
+		if err != nil {
+			return Fail(err)
+		}
+		return ETagged(http.StatusOK, result)
+	}
+	result, err := s.FetchAll()
+	if err != nil {
+		return Fail(err)
+	}
+	return JSON(http.StatusOK, result)
+}
+
+func create[T any](s Service[T], req Request) (*Response, error) {
+	v, err := Decode[T](req)
+	if err != nil {
+		return Fail(err)
+	}
+	result, err := s.Create(v, Actor(req))
+	if err != nil {
+		return Fail(err)
+	}
+	return ETagged(http.StatusCreated, result)
+}
+
+func update[T any](s Service[T], req Request) (*Response, error) {
+	v, err := Decode[T](req)
+	if err != nil {
+		return Fail(err)
+	}
+	version, err := IfMatch(req)
+	if err != nil {
+		return Fail(err)
+	}
+	setVersion(v, version)
+	result, err := s.Update(v, Actor(req))
+	if err != nil {
+		return Fail(err)
+	}
+	return ETagged(http.StatusOK, result)
+}
+
+func remove[T any](s Service[T], req Request) (*Response, error) {
+	queryid := req.QueryStringParameters["id"]
+	id, _ := strconv.Atoi(queryid)
+	version, err := IfMatch(req)
+	if err != nil {
+		return Fail(err)
+	}
+	err = s.Delete(id, version, Actor(req))
+	if err != nil {
+		return Fail(err)
+	}
+	return JSON(http.StatusOK, nil)
+}
+
+// setVersion sets the version v must still have to be written, when v is Versioned.
+func setVersion(v interface{}, version int) {
+	if m, ok := v.(Versioned); ok {
+		m.Metadata().Version = version
+	}
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"strings"
+
+	"fazendadojuca.com.br/internal/audit"
+)
+
+const maxActorLen = 128
+
+// Actor tells who made a request: the principal of the API Gateway authorizer,
+// and the name claimed by the client in the X-Actor header, which is only
+// recorded as such.
+func Actor(req Request) audit.Actor {
+	principal, _ := req.RequestContext.Authorizer["principalId"].(string)
+	if principal == "" {
+		principal = audit.Anonymous
+	}
+	return audit.Actor{
+		Principal: truncate(principal, maxActorLen),
+		Claimed:   truncate(strings.TrimSpace(Header(req, "X-Actor")), maxActorLen),
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
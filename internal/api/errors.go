@@ -0,0 +1,95 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// Machine readable error codes sent in ErrorBody.Code.
+const (
+	CodeValidation = "validation"
+	CodeNotFound   = "not_found"
+	CodeConflict   = "conflict"
+	CodeInternal   = "internal"
+)
+
+// Error is an error with the HTTP status and code it is reported with.
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+	// Details is sent along with the message, e.g. the rows behind a conflict.
+	Details interface{}
+	// Fields lists the invalid fields of a payload.
+	Fields []FieldError
+	Err    error
+}
+
+// FieldError reports why a single field of a payload was rejected.
+type FieldError struct {
+	Field string `json:"field"`
+	Code  string `json:"code"`
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func Validation(msg string) *Error {
+	return &Error{Status: http.StatusBadRequest, Code: CodeValidation, Message: msg}
+}
+
+// Invalid reports a payload rejected because of the given fields.
+func Invalid(fields []FieldError) *Error {
+	return &Error{Status: http.StatusBadRequest, Code: CodeValidation, Message: "Invalid Data", Fields: fields}
+}
+
+func NotFound(msg string) *Error {
+	return &Error{Status: http.StatusNotFound, Code: CodeNotFound, Message: msg}
+}
+
+func Conflict(msg string) *Error {
+	return &Error{Status: http.StatusConflict, Code: CodeConflict, Message: msg}
+}
+
+// Internal wraps an unexpected error, usually from the database. Its cause is
+// logged but never sent to the client.
+func Internal(err error) *Error {
+	return &Error{Status: http.StatusInternalServerError, Code: CodeInternal, Message: "Internal Error", Err: err}
+}
+
+// Fail writes err as an error response. Errors that are not an *Error are
+// reported as internal errors.
+func Fail(err error) (*Response, error) {
+	var e *Error
+	if !errors.As(err, &e) {
+		e = Internal(err)
+	}
+	if e.Status == http.StatusInternalServerError {
+		log.Printf("api: %v", e)
+	}
+	msg := e.Message
+	return JSON(e.Status, ErrorBody{ErrorMsg: &msg, Code: e.Code, Details: e.Details, Errors: e.Fields})
+}
+
+// Recover turns a panic in h into an internal error response so a single
+// request can never crash the container.
+func Recover(h HandlerFunc) HandlerFunc {
+	return func(req Request) (resp *Response, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				resp, err = Fail(Internal(fmt.Errorf("panic: %v", r)))
+			}
+		}()
+		return h(req)
+	}
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// MergePatch applies the JSON Merge Patch of RFC 7396 to target: objects are
+// merged member by member, a null member removes it and any other value
+// replaces it.
+func MergePatch(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = map[string]interface{}{}
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = MergePatch(t[k], v)
+	}
+	return t
+}
+
+// patch applies the merge patch in the body to the stored record of the id
+// query parameter and saves the result through s.Update. The id itself cannot
+// be patched, and the update only applies to the version the patch was merged
+// into, which must also match If-Match when given.
+func patch[T any](s Service[T], req Request) (*Response, error) {
+	id, err := strconv.Atoi(req.QueryStringParameters["id"])
+	if err != nil || id == 0 {
+		return Fail(Validation("Invalid ID"))
+	}
+	var p interface{}
+	if err := json.Unmarshal([]byte(req.Body), &p); err != nil {
+		return Fail(Validation("Invalid Data"))
+	}
+	if _, ok := p.(map[string]interface{}); !ok {
+		return Fail(Validation("Invalid Data"))
+	}
+	expected, err := IfMatch(req)
+	if err != nil {
+		return Fail(err)
+	}
+	current, err := s.FetchOne(id)
+	if err != nil {
+		return Fail(err)
+	}
+	version := 0
+	if m, ok := interface{}(current).(Versioned); ok {
+		version = m.Metadata().Version
+	}
+	if expected != 0 && expected != version {
+		return Fail(PreconditionFailed("Version Mismatch"))
+	}
+	var doc interface{}
+	b, err := json.Marshal(current)
+	if err == nil {
+		err = json.Unmarshal(b, &doc)
+	}
+	if err != nil {
+		return Fail(Internal(err))
+	}
+	merged := MergePatch(doc, p).(map[string]interface{})
+	merged["id"] = id
+	b, err = json.Marshal(merged)
+	if err != nil {
+		return Fail(Internal(err))
+	}
+	v := new(T)
+	if err := json.Unmarshal(b, v); err != nil {
+		return Fail(Validation("Invalid Data"))
+	}
+	setVersion(v, version)
+	result, err := s.Update(v, Actor(req))
+	if err != nil {
+		return Fail(err)
+	}
+	return ETagged(http.StatusOK, result)
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Meta is embedded in every record. Version starts at 1 and is bumped by every
+// update, so a client can tell whether the record changed since it read it.
+type Meta struct {
+	Version   int       `json:"version,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Metadata gives access to the embedded Meta of a record.
+func (m *Meta) Metadata() *Meta { return m }
+
+// Versioned is implemented by every record embedding Meta.
+type Versioned interface {
+	Metadata() *Meta
+}
+
+const CodePreconditionFailed = "precondition_failed"
+
+// PreconditionFailed reports a write whose If-Match no longer matches the record.
+func PreconditionFailed(msg string) *Error {
+	return &Error{Status: http.StatusPreconditionFailed, Code: CodePreconditionFailed, Message: msg}
+}
+
+// ETag is the entity tag of version.
+func ETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// Header returns the first value of the request header name, whatever the case
+// API Gateway delivered it in.
+func Header(req Request, name string) string {
+	for k, v := range req.Headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	for k, vs := range req.MultiValueHeaders {
+		if strings.EqualFold(k, name) && len(vs) > 0 {
+			return vs[0]
+		}
+	}
+	return ""
+}
+
+// IfMatch returns the version required by the If-Match header, or 0 when the
+// header is absent or "*". An entity tag that is not one of ours can never
+// match, so it fails right away.
+func IfMatch(req Request) (int, error) {
+	v := strings.TrimSpace(Header(req, "If-Match"))
+	if v == "" || v == "*" {
+		return 0, nil
+	}
+	v = strings.Trim(strings.TrimPrefix(v, "W/"), `"`)
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return 0, PreconditionFailed("Version Mismatch")
+	}
+	return n, nil
+}
+
+// ETagged writes body like JSON, with the ETag of its version when it has one.
+func ETagged(status int, body interface{}) (*Response, error) {
+	resp, err := JSON(status, body)
+	if v, ok := body.(Versioned); ok && resp != nil && v.Metadata().Version != 0 {
+		resp.Headers["ETag"] = ETag(v.Metadata().Version)
+	}
+	return resp, err
+}
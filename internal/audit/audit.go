@@ -0,0 +1,174 @@
+// Package audit keeps the history of every record: who created, changed or
+// deleted it, when, and the fields that changed.
+package audit
+
+import (
+	"encoding/json"
+	"time"
+
+	"fazendadojuca.com.br/internal/database"
+)
+
+// Actions recorded in Entry.Action.
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+	ActionPurge   = "purge"
+)
+
+// Change holds the JSON value of a field before and after a change, null when
+// the record did not exist.
+type Change struct {
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// Actor is who made a change. Principal is the one authenticated by API
+// Gateway, Anonymous when there is none. Claimed is the name the client gave
+// for itself, which nothing checks.
+type Actor struct {
+	Principal string
+	Claimed   string
+}
+
+const Anonymous = "anonymous"
+
+type Entry struct {
+	ID       int               `json:"id"`
+	At       time.Time         `json:"at"`
+	Actor    string            `json:"actor"`
+	Claimed  *string           `json:"claimed_actor,omitempty"`
+	Entity   string            `json:"entity"`
+	EntityID int               `json:"entity_id"`
+	Action   string            `json:"action"`
+	Changes  map[string]Change `json:"changes"`
+}
+
+// ignored are the fields every write changes, which tell nothing about it.
+var ignored = map[string]bool{"id": true, "version": true, "updated_at": true}
+
+// fields returns the JSON members of v, none when v is nil.
+func fields(v interface{}) (map[string]json.RawMessage, error) {
+	m := map[string]json.RawMessage{}
+	b, err := json.Marshal(v)
+	if err != nil || string(b) == "null" {
+		return m, err
+	}
+	return m, json.Unmarshal(b, &m)
+}
+
+// Diff compares the JSON of a record before and after a change, either being
+// nil when the record did not exist. It returns the id of the record and the
+// fields that changed.
+func Diff(before, after interface{}) (int, map[string]Change, error) {
+	b, err := fields(before)
+	if err != nil {
+		return 0, nil, err
+	}
+	a, err := fields(after)
+	if err != nil {
+		return 0, nil, err
+	}
+	var id int
+	if raw, ok := a["id"]; ok {
+		err = json.Unmarshal(raw, &id)
+	} else if raw, ok := b["id"]; ok {
+		err = json.Unmarshal(raw, &id)
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	changes := map[string]Change{}
+	for _, m := range []map[string]json.RawMessage{b, a} {
+		for k := range m {
+			before, after := value(b, k), value(a, k)
+			if !ignored[k] && string(before) != string(after) {
+				changes[k] = Change{Before: before, After: after}
+			}
+		}
+	}
+	return id, changes, nil
+}
+
+// value returns the member k of m, null when absent.
+func value(m map[string]json.RawMessage, k string) json.RawMessage {
+	if v, ok := m[k]; ok {
+		return v
+	}
+	return json.RawMessage("null")
+}
+
+// Record keeps a change to a record of entity made by actor, within the
+// transaction making the change so that neither is kept without the other.
+// Updates that changed nothing are left out.
+func Record(tx database.Querier, actor Actor, entity, action string, before, after interface{}) error {
+	id, changes, err := Diff(before, after)
+	if err != nil {
+		return err
+	}
+	if action == ActionUpdate && len(changes) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+	INSERT INTO audit_log (
+		at,
+		actor,
+		claimed_actor,
+		entity,
+		entity_id,
+		action,
+		changes
+	) VALUES (CURRENT_TIMESTAMP, ?, ?, ?, ?, ?, ?);`,
+		actor.Principal, claimed(actor), entity, id, action, string(b))
+	return err
+}
+
+// claimed returns the claimed name of actor, nil when it gave none.
+func claimed(actor Actor) *string {
+	if actor.Claimed == "" {
+		return nil
+	}
+	return &actor.Claimed
+}
+
+// History returns the changes made to a record of entity, oldest first.
+func History(entity string, id int) ([]*Entry, error) {
+	db := database.DB()
+	results, err := db.Query(`
+	SELECT
+		id,
+		at,
+		actor,
+		claimed_actor,
+		entity,
+		entity_id,
+		action,
+		changes
+	FROM audit_log
+	WHERE entity = ? AND entity_id = ?
+	ORDER BY id`, entity, id)
+	if err != nil {
+		return nil, err
+	}
+	defer results.Close()
+	es := []*Entry{}
+	for results.Next() {
+		e := new(Entry)
+		var changes string
+		err = results.Scan(&e.ID, &e.At, &e.Actor, &e.Claimed, &e.Entity, &e.EntityID, &e.Action, &changes)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(changes), &e.Changes); err != nil {
+			return nil, err
+		}
+		es = append(es, e)
+	}
+	return es, results.Err()
+}
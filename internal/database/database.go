@@ -0,0 +1,107 @@
+// Package database holds the connection pool behind every service, on the
+// storage backend selected by DB_BACKEND: "mysql" (the default, configured
+// through the other DB_* environment variables), "sqlite" (a file at DB_PATH)
+// or "memory" (a private SQLite database that lives as long as the process).
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const pingTimeout = 5 * time.Second
+
+var (
+	pool    *sql.DB
+	backend Backend
+	once    sync.Once
+)
+
+// Backend is a storage engine the services can run on. The services only
+// write SQL understood by every backend; the schema itself comes from the
+// migrations of the backend's dialect.
+type Backend interface {
+	// Dialect names the SQL dialect: "mysql" or "sqlite".
+	Dialect() string
+	Open() (*sql.DB, error)
+	// IsForeignKeyError reports whether err is a foreign key violation,
+	// either on a referenced row being deleted or on a missing referenced row.
+	IsForeignKeyError(err error) bool
+}
+
+var backends = map[string]func() Backend{
+	"mysql":  func() Backend { return mysqlBackend{} },
+	"sqlite": func() Backend { return sqliteBackend{path: envString("DB_PATH", "fazendadojuca.db")} },
+	"memory": func() Backend { return memoryBackend{} },
+}
+
+// Scanner is implemented by both *sql.Row and *sql.Rows.
+type Scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// Current returns the backend selected by DB_BACKEND.
+func Current() Backend {
+	DB()
+	return backend
+}
+
+// DB returns the connection pool shared by every invocation of the container.
+// The pool is created and pinged on the first call.
+func DB() *sql.DB {
+	once.Do(func() {
+		name := envString("DB_BACKEND", "mysql")
+		newBackend, ok := backends[name]
+		if !ok {
+			// Only a misconfigured environment gets here; the handlers
+			// recover the panic into an internal error response.
+			panic(fmt.Sprintf("database: unknown DB_BACKEND %q", name))
+		}
+		backend = newBackend()
+		var err error
+		if pool, err = backend.Open(); err != nil {
+			panic(fmt.Sprintf("database: opening %s: %v", name, err))
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+		defer cancel()
+		if err := pool.PingContext(ctx); err != nil {
+			log.Printf("database: cold start ping failed: %v", err)
+		}
+	})
+	return pool
+}
+
+// IsForeignKeyError reports whether err is a foreign key violation on the
+// current backend.
+func IsForeignKeyError(err error) bool {
+	return err != nil && Current().IsForeignKeyError(err)
+}
+
+func envString(name, def string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return def
+}
+
+func envInt(name string, def int) int {
+	n, err := strconv.Atoi(os.Getenv(name))
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+func envDuration(name string, def time.Duration) time.Duration {
+	d, err := time.ParseDuration(os.Getenv(name))
+	if err != nil {
+		return def
+	}
+	return d
+}
@@ -0,0 +1,112 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// MySQL errors returned by an instance that was demoted to a reader by an RDS
+// failover. The statement was not executed, so it is safe to retry it on a new
+// connection, which resolves the cluster endpoint to the new writer.
+var failoverErrors = map[uint16]bool{
+	1290: true, // ER_OPTION_PREVENTS_STATEMENT (--read-only)
+	1792: true, // ER_CANT_EXECUTE_IN_READ_ONLY_TRANSACTION
+	1836: true, // ER_READ_ONLY_MODE
+}
+
+func isFailover(err error) bool {
+	var me *mysql.MySQLError
+	return errors.As(err, &me) && failoverErrors[me.Number]
+}
+
+// failoverConnector wraps the MySQL connections so failover errors are
+// reported as driver.ErrBadConn, which makes database/sql discard the
+// connection and retry the statement on a fresh one.
+type failoverConnector struct {
+	driver.Connector
+}
+
+func (c failoverConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &failoverConn{Conn: conn}, nil
+}
+
+type failoverConn struct {
+	driver.Conn
+	bad bool
+}
+
+func (c *failoverConn) check(err error) error {
+	if isFailover(err) {
+		c.bad = true
+		return driver.ErrBadConn
+	}
+	return err
+}
+
+func (c *failoverConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	s, err := c.Conn.(driver.ConnPrepareContext).PrepareContext(ctx, query)
+	if err != nil {
+		return nil, c.check(err)
+	}
+	return &failoverStmt{Stmt: s, conn: c}, nil
+}
+
+func (c *failoverConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	r, err := c.Conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+	return r, c.check(err)
+}
+
+func (c *failoverConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	r, err := c.Conn.(driver.QueryerContext).QueryContext(ctx, query, args)
+	return r, c.check(err)
+}
+
+func (c *failoverConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	tx, err := c.Conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+	return tx, c.check(err)
+}
+
+func (c *failoverConn) Ping(ctx context.Context) error {
+	return c.check(c.Conn.(driver.Pinger).Ping(ctx))
+}
+
+func (c *failoverConn) CheckNamedValue(nv *driver.NamedValue) error {
+	return c.Conn.(driver.NamedValueChecker).CheckNamedValue(nv)
+}
+
+func (c *failoverConn) ResetSession(ctx context.Context) error {
+	if c.bad {
+		return driver.ErrBadConn
+	}
+	return c.Conn.(driver.SessionResetter).ResetSession(ctx)
+}
+
+func (c *failoverConn) IsValid() bool {
+	return !c.bad
+}
+
+type failoverStmt struct {
+	driver.Stmt
+	conn *failoverConn
+}
+
+func (s *failoverStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	r, err := s.Stmt.(driver.StmtExecContext).ExecContext(ctx, args)
+	return r, s.conn.check(err)
+}
+
+func (s *failoverStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	r, err := s.Stmt.(driver.StmtQueryContext).QueryContext(ctx, args)
+	return r, s.conn.check(err)
+}
+
+func (s *failoverStmt) ColumnConverter(idx int) driver.ValueConverter {
+	return s.Stmt.(driver.ColumnConverter).ColumnConverter(idx)
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"net"
+	"os"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// mysqlBackend is the RDS database the Lambdas run against.
+type mysqlBackend struct{}
+
+func (mysqlBackend) Dialect() string { return "mysql" }
+
+func (mysqlBackend) Open() (*sql.DB, error) {
+	cfg := mysql.NewConfig()
+	cfg.User = os.Getenv("DB_USERNAME")
+	cfg.Passwd = os.Getenv("DB_PASSWORD")
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(os.Getenv("DB_ENDPOINT"), os.Getenv("DB_PORT"))
+	cfg.DBName = os.Getenv("DB_NAME")
+	cfg.AllowNativePasswords = true
+	cfg.ParseTime = true
+	// Report matched rather than changed rows, so an UPDATE that leaves a
+	// row as it was is not mistaken for a missing id.
+	cfg.ClientFoundRows = true
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, err
+	}
+	db := sql.OpenDB(failoverConnector{connector})
+	db.SetMaxOpenConns(envInt("DB_MAX_OPEN_CONNS", 5))
+	db.SetMaxIdleConns(envInt("DB_MAX_IDLE_CONNS", 2))
+	db.SetConnMaxLifetime(envDuration("DB_CONN_MAX_LIFETIME", 5*time.Minute))
+	db.SetConnMaxIdleTime(envDuration("DB_CONN_MAX_IDLE_TIME", time.Minute))
+	return db, nil
+}
+
+func (mysqlBackend) IsForeignKeyError(err error) bool {
+	var me *mysql.MySQLError
+	return errors.As(err, &me) && (me.Number == 1451 || me.Number == 1452)
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"strings"
+
+	"modernc.org/sqlite"
+
+	"fazendadojuca.com.br/internal/migrate"
+	"fazendadojuca.com.br/migrations"
+)
+
+// SQLITE_CONSTRAINT_FOREIGNKEY, and SQLITE_CONSTRAINT_TRIGGER which SQLite
+// reports when an ON DELETE RESTRICT action refuses the statement.
+const (
+	sqliteConstraintForeignKey = 787
+	sqliteConstraintTrigger    = 1811
+)
+
+// sqliteBackend keeps the whole farm in a single file, for running offline.
+// Its schema is managed with cmd/migrate like the MySQL one.
+type sqliteBackend struct {
+	path string
+}
+
+func (sqliteBackend) Dialect() string { return "sqlite" }
+
+func (b sqliteBackend) Open() (*sql.DB, error) {
+	return openSQLite("file:" + b.path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+}
+
+func (sqliteBackend) IsForeignKeyError(err error) bool {
+	return isSQLiteForeignKeyError(err)
+}
+
+// memoryBackend is an SQLite database that only lives in memory, migrated to
+// the latest schema when opened. Everything is lost when the process exits.
+type memoryBackend struct{}
+
+func (memoryBackend) Dialect() string { return "sqlite" }
+
+func (memoryBackend) Open() (*sql.DB, error) {
+	db, err := openSQLite("file::memory:?_pragma=foreign_keys(1)")
+	if err != nil {
+		return nil, err
+	}
+	ms, err := migrate.Load(migrations.For("sqlite"))
+	if err != nil {
+		return nil, err
+	}
+	r := &migrate.Runner{DB: db, Migrations: ms, Out: io.Discard}
+	if err := r.Up(0); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+func (memoryBackend) IsForeignKeyError(err error) bool {
+	return isSQLiteForeignKeyError(err)
+}
+
+// openSQLite opens dsn on a single connection that is never recycled: SQLite
+// serializes writers anyway, the in-memory database only exists as long as its
+// connection, and migrations rely on per-connection PRAGMAs.
+func openSQLite(dsn string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	db.SetConnMaxLifetime(0)
+	db.SetConnMaxIdleTime(0)
+	return db, nil
+}
+
+func isSQLiteForeignKeyError(err error) bool {
+	var se *sqlite.Error
+	if !errors.As(err, &se) {
+		return false
+	}
+	switch se.Code() {
+	case sqliteConstraintForeignKey:
+		return true
+	case sqliteConstraintTrigger:
+		return strings.Contains(se.Error(), "FOREIGN KEY")
+	}
+	return false
+}
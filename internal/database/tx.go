@@ -0,0 +1,36 @@
+package database
+
+import "database/sql"
+
+// Querier is implemented by both *sql.DB and *sql.Tx, so the same query can
+// run on its own or as part of a transaction.
+type Querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// InTx runs fn in a transaction, committed when fn returns nil and rolled back
+// otherwise. fn must only use tx: the SQLite backends have a single
+// connection, which the transaction holds until it ends.
+func InTx(fn func(tx *sql.Tx) error) error {
+	tx, err := DB().Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// ForUpdate is the clause that locks the rows a transaction reads until it
+// ends. SQLite has none and needs none: its single connection already runs one
+// transaction at a time.
+func ForUpdate() string {
+	if Current().Dialect() == "mysql" {
+		return " FOR UPDATE"
+	}
+	return ""
+}
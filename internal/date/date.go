@@ -0,0 +1,92 @@
+// Package date holds a calendar date without time of day, written as
+// ISO-8601 (YYYY-MM-DD) both in JSON and in the database.
+package date
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Layout is the ISO-8601 calendar date layout.
+const Layout = "2006-01-02"
+
+// Date is a calendar date at midnight UTC.
+type Date struct {
+	time.Time
+}
+
+// Parse parses s as YYYY-MM-DD.
+func Parse(s string) (Date, error) {
+	t, err := time.Parse(Layout, s)
+	if err != nil {
+		return Date{}, err
+	}
+	return Date{t}, nil
+}
+
+// Today returns the current date.
+func Today() Date {
+	return Of(time.Now())
+}
+
+// Of returns the date of t in its own location.
+func Of(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{time.Date(y, m, d, 0, 0, 0, 0, time.UTC)}
+}
+
+func (d Date) String() string {
+	return d.Format(Layout)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// Value stores the date as YYYY-MM-DD, which MySQL DATE and SQLite TEXT
+// columns both accept and compare in calendar order.
+func (d Date) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan reads a time.Time, as returned by MySQL with parseTime=true, or the
+// YYYY-MM-DD text SQLite stores.
+func (d *Date) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case time.Time:
+		*d = Of(v)
+		return nil
+	case []byte:
+		return d.scanString(string(v))
+	case string:
+		return d.scanString(v)
+	}
+	return fmt.Errorf("date: cannot scan %T", src)
+}
+
+func (d *Date) scanString(s string) error {
+	if len(s) > len(Layout) {
+		s = s[:len(Layout)]
+	}
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
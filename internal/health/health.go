@@ -0,0 +1,86 @@
+// Package health implements the health function: the vaccinations, dewormings
+// and treatments of each animal, and the animals still under a withdrawal
+// period that must not be sold or slaughtered.
+package health
+
+import (
+	"strings"
+
+	"fazendadojuca.com.br/internal/api"
+	"fazendadojuca.com.br/internal/date"
+	"fazendadojuca.com.br/internal/repository"
+	"fazendadojuca.com.br/internal/validation"
+)
+
+var (
+	types  = []string{"vaccination", "deworming", "treatment"}
+	routes = []string{"intramuscular", "subcutaneous", "intravenous", "oral", "topical", "intranasal", "intramammary"}
+)
+
+// event is a single vaccination, deworming or treatment of animal.
+// WithdrawalEnd is the last day its meat and milk may not be sold, if any.
+type event struct {
+	ID            int        `json:"id,omitempty"`
+	Animal        int        `json:"animal"`
+	Date          date.Date  `json:"date"`
+	Type          string     `json:"type"`
+	Product       string     `json:"product"`
+	Dose          string     `json:"dose"`
+	Route         string     `json:"route"`
+	Vet           string     `json:"vet"`
+	WithdrawalEnd *date.Date `json:"withdrawal_end"`
+	Notes         string     `json:"notes"`
+	api.Meta
+}
+
+var eventRepo = repository.New(repository.Entity[event]{
+	Name:    "Health Event",
+	Table:   "health_event",
+	Columns: []string{"animal", "date", "type", "product", "dose", "route", "vet", "withdrawal_end", "notes"},
+	ID:      func(e *event) *int { return &e.ID },
+	Fields: func(e *event) []interface{} {
+		return []interface{}{&e.Animal, &e.Date, &e.Type, &e.Product, &e.Dose, &e.Route, &e.Vet, &e.WithdrawalEnd, &e.Notes}
+	},
+	Validate: validate,
+})
+
+func validate(e *event) error {
+	if e.Animal == 0 {
+		return api.Validation("Animal Required")
+	}
+	if e.Date.IsZero() {
+		return api.Validation("Invalid Date")
+	}
+	if e.Date.After(date.Today().Time) {
+		return api.Validation("Date In The Future")
+	}
+	if !validation.OneOf(e.Type, types) {
+		return api.Validation("Invalid Type")
+	}
+	e.Product = strings.TrimSpace(e.Product)
+	if e.Product == "" {
+		return api.Validation("Product Required")
+	}
+	if !validation.OneOf(e.Route, routes) {
+		return api.Validation("Invalid Route")
+	}
+	if e.WithdrawalEnd != nil && e.WithdrawalEnd.Before(e.Date.Time) {
+		return api.Validation("Withdrawal End Before Date")
+	}
+	e.Dose = strings.TrimSpace(e.Dose)
+	e.Vet = strings.TrimSpace(e.Vet)
+	e.Notes = strings.TrimSpace(e.Notes)
+	return nil
+}
+
+var crud = api.Handler[event](eventRepo)
+
+// Handler is the API Gateway handler of the health function.
+var Handler = api.Recover(handler)
+
+func handler(req api.Request) (*api.Response, error) {
+	if req.HTTPMethod == "GET" && strings.HasSuffix(req.Path, "/withdrawal") {
+		return getWithdrawal(req)
+	}
+	return crud(req)
+}
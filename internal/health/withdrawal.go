@@ -0,0 +1,91 @@
+package health
+
+import (
+	"net/http"
+
+	"fazendadojuca.com.br/internal/api"
+	"fazendadojuca.com.br/internal/database"
+	"fazendadojuca.com.br/internal/date"
+)
+
+// withdrawal is a live animal under at least one withdrawal period on a date.
+// WithdrawalEnd is the latest end among Events.
+type withdrawal struct {
+	Animal        int                `json:"animal"`
+	Name          string             `json:"name"`
+	Number        string             `json:"number"`
+	WithdrawalEnd date.Date          `json:"withdrawal_end"`
+	Events        []*withdrawalEvent `json:"events"`
+}
+
+type withdrawalEvent struct {
+	ID            int       `json:"id"`
+	Date          date.Date `json:"date"`
+	Type          string    `json:"type"`
+	Product       string    `json:"product"`
+	WithdrawalEnd date.Date `json:"withdrawal_end"`
+}
+
+const withdrawalSelect = `
+	SELECT
+		a.id,
+		a.name,
+		a.number,
+		e.id,
+		e.date,
+		e.type,
+		e.product,
+		e.withdrawal_end
+	FROM health_event e
+		JOIN animal a ON a.id = e.animal
+	WHERE e.withdrawal_end >= ?
+		AND a.death IS NULL
+		AND a.deleted_at IS NULL
+	ORDER BY a.id, e.date, e.id`
+
+func getWithdrawal(req api.Request) (*api.Response, error) {
+	on := date.Today()
+	if v := req.QueryStringParameters["date"]; v != "" {
+		d, err := date.Parse(v)
+		if err != nil {
+			return api.Fail(api.Validation("Invalid Date"))
+		}
+		on = d
+	}
+	result, err := serviceWithdrawal(on)
+	if err != nil {
+		return api.Fail(err)
+	}
+	return api.JSON(http.StatusOK, result)
+}
+
+// serviceWithdrawal lists the live animals with a withdrawal period not over on date.
+func serviceWithdrawal(on date.Date) ([]*withdrawal, error) {
+	db := database.DB()
+	results, err := db.Query(withdrawalSelect, on)
+	if err != nil {
+		return nil, api.Internal(err)
+	}
+	defer results.Close()
+	ws := []*withdrawal{}
+	for results.Next() {
+		w, e := new(withdrawal), new(withdrawalEvent)
+		err := results.Scan(&w.Animal, &w.Name, &w.Number, &e.ID, &e.Date, &e.Type, &e.Product, &e.WithdrawalEnd)
+		if err != nil {
+			return nil, api.Internal(err)
+		}
+		if n := len(ws); n > 0 && ws[n-1].Animal == w.Animal {
+			w = ws[n-1]
+		} else {
+			ws = append(ws, w)
+		}
+		w.Events = append(w.Events, e)
+		if e.WithdrawalEnd.After(w.WithdrawalEnd.Time) {
+			w.WithdrawalEnd = e.WithdrawalEnd
+		}
+	}
+	if err := results.Err(); err != nil {
+		return nil, api.Internal(err)
+	}
+	return ws, nil
+}
@@ -0,0 +1,157 @@
+// Package insemination implements the insemination function: the artificial
+// insemination services of the herd and the conception rates per bull and per
+// technician.
+package insemination
+
+import (
+	"database/sql"
+	"strings"
+
+	"fazendadojuca.com.br/internal/api"
+	"fazendadojuca.com.br/internal/audit"
+	"fazendadojuca.com.br/internal/database"
+	"fazendadojuca.com.br/internal/date"
+	"fazendadojuca.com.br/internal/lookup"
+	"fazendadojuca.com.br/internal/repository"
+	"fazendadojuca.com.br/internal/validation"
+)
+
+// Outcomes of a service. A calf linked to the service marks it calved.
+const (
+	OutcomePending  = "pending"
+	OutcomePregnant = "pregnant"
+	OutcomeOpen     = "open"
+	OutcomeCalved   = "calved"
+)
+
+var outcomes = []string{OutcomePending, OutcomePregnant, OutcomeOpen, OutcomeCalved}
+
+// insemination is a single service of cow by a herd bull or an external one,
+// such as a semen-bank sire.
+type insemination struct {
+	ID           int       `json:"id,omitempty"`
+	Date         date.Date `json:"date"`
+	Cow          int       `json:"cow"`
+	Bull         *int      `json:"bull"`
+	ExternalBull *int      `json:"external_bull"`
+	Technician   string    `json:"technician"`
+	StrawBatch   string    `json:"straw_batch"`
+	Outcome      string    `json:"outcome"`
+	api.Meta
+}
+
+var inseminationRepo = repository.New(repository.Entity[insemination]{
+	Name:    "Insemination",
+	Table:   "insemination",
+	Columns: []string{"date", "cow", "bull", "external_bull", "technician", "straw_batch", "outcome"},
+	ID:      func(i *insemination) *int { return &i.ID },
+	Fields: func(i *insemination) []interface{} {
+		return []interface{}{&i.Date, &i.Cow, &i.Bull, &i.ExternalBull, &i.Technician, &i.StrawBatch, &i.Outcome}
+	},
+	References: []repository.Reference{{Table: "animal", Column: "insemination_id"}},
+	Validate:   validate,
+})
+
+func validate(i *insemination) error {
+	if i.Date.IsZero() {
+		return api.Validation("Invalid Date")
+	}
+	if i.Date.After(date.Today().Time) {
+		return api.Validation("Date In The Future")
+	}
+	if i.Cow == 0 {
+		return api.Validation("Cow Required")
+	}
+	if (i.Bull == nil) == (i.ExternalBull == nil) {
+		return api.Validation("Either Bull Or External Bull Required")
+	}
+	if err := checkSex(i.Cow, lookup.SexFemale, "Cow Not Found", "Cow Must Be Female"); err != nil {
+		return err
+	}
+	if i.Bull != nil {
+		if err := checkSex(*i.Bull, lookup.SexMale, "Bull Not Found", "Bull Must Be Male"); err != nil {
+			return err
+		}
+	}
+	i.Technician = strings.TrimSpace(i.Technician)
+	i.StrawBatch = strings.TrimSpace(i.StrawBatch)
+	if i.Outcome == "" {
+		i.Outcome = OutcomePending
+	}
+	if !validation.OneOf(i.Outcome, outcomes) {
+		return api.Validation("Invalid Outcome")
+	}
+	return nil
+}
+
+// checkSex fails with notFound unless the herd animal id exists and is not
+// deleted, and with wrongSex unless it is of a gender of the given sex.
+func checkSex(id int, sex, notFound, wrongSex string) error {
+	var s *string
+	db := database.DB()
+	row := db.QueryRow(`
+	SELECT g.sex
+	FROM animal a
+		JOIN gender g ON g.id = a.gender_id
+	WHERE a.id = ? AND a.deleted_at IS NULL`, id)
+	err := row.Scan(&s)
+	if err == sql.ErrNoRows {
+		return api.Validation(notFound)
+	}
+	if err != nil {
+		return api.Internal(err)
+	}
+	if s == nil || *s != sex {
+		return api.Validation(wrongSex)
+	}
+	return nil
+}
+
+// MarkCalved records within tx that service id ended in calving, as told by actor.
+func MarkCalved(tx database.Querier, id int, actor audit.Actor) error {
+	i, err := inseminationRepo.Lock(tx, id)
+	if err != nil {
+		return err
+	}
+	if i.Outcome == OutcomeCalved {
+		return nil
+	}
+	i.Outcome = OutcomeCalved
+	_, err = inseminationRepo.UpdateIn(tx, i, actor)
+	return err
+}
+
+// UnmarkCalved puts service id back to pending once no calf is linked to it
+// anymore, as told by actor.
+func UnmarkCalved(tx database.Querier, id int, actor audit.Actor) error {
+	i, err := inseminationRepo.Lock(tx, id)
+	if err != nil {
+		return err
+	}
+	if i.Outcome != OutcomeCalved {
+		return nil
+	}
+	var calves int
+	err = tx.QueryRow("SELECT COUNT(*) FROM animal WHERE insemination_id = ?", id).Scan(&calves)
+	if err != nil {
+		return api.Internal(err)
+	}
+	if calves > 0 {
+		return nil
+	}
+	i.Outcome = OutcomePending
+	_, err = inseminationRepo.UpdateIn(tx, i, actor)
+	return err
+}
+
+var crud = api.Handler[insemination](inseminationRepo)
+
+// Handler is the API Gateway handler of the insemination function.
+var Handler = api.Recover(handler)
+
+func handler(req api.Request) (*api.Response, error) {
+	if req.HTTPMethod == "GET" && strings.HasSuffix(req.Path, "/rates") {
+		return getRates(req)
+	}
+	return crud(req)
+}
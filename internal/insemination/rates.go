@@ -0,0 +1,111 @@
+package insemination
+
+import (
+	"net/http"
+
+	"fazendadojuca.com.br/internal/api"
+	"fazendadojuca.com.br/internal/database"
+	"fazendadojuca.com.br/internal/date"
+)
+
+// rate is the conception rate of a bull or technician over the services with a
+// known outcome: pregnant or calved out of every resolved service.
+type rate struct {
+	Bull       *int    `json:"bull,omitempty"`
+	External   bool    `json:"external,omitempty"`
+	Name       string  `json:"name,omitempty"`
+	Technician *string `json:"technician,omitempty"`
+	Services   int     `json:"services"`
+	Conceived  int     `json:"conceived"`
+	Rate       float64 `json:"rate"`
+}
+
+type rates struct {
+	Bulls       []*rate `json:"bulls"`
+	Technicians []*rate `json:"technicians"`
+}
+
+const conceived = `SUM(CASE WHEN i.outcome IN ('` + OutcomePregnant + `', '` + OutcomeCalved + `') THEN 1 ELSE 0 END)`
+
+const bullRates = `
+	SELECT
+		IFNULL(i.bull, i.external_bull),
+		i.external_bull IS NOT NULL,
+		IFNULL(a.name, e.name),
+		COUNT(*),
+		` + conceived + `
+	FROM insemination i
+		LEFT JOIN animal a ON a.id = i.bull
+		LEFT JOIN external_parent e ON e.id = i.external_bull`
+
+const technicianRates = `
+	SELECT
+		i.technician,
+		COUNT(*),
+		` + conceived + `
+	FROM insemination i`
+
+func getRates(req api.Request) (*api.Response, error) {
+	where, args := " WHERE i.outcome <> ?", []interface{}{OutcomePending}
+	for _, f := range []struct{ param, cond string }{
+		{"from", " AND i.date >= ?"},
+		{"to", " AND i.date <= ?"},
+	} {
+		v := req.QueryStringParameters[f.param]
+		if v == "" {
+			continue
+		}
+		d, err := date.Parse(v)
+		if err != nil {
+			return api.Fail(api.Validation("Invalid " + f.param))
+		}
+		where += f.cond
+		args = append(args, d)
+	}
+	result, err := serviceRates(where, args)
+	if err != nil {
+		return api.Fail(err)
+	}
+	return api.JSON(http.StatusOK, result)
+}
+
+func serviceRates(where string, args []interface{}) (*rates, error) {
+	db := database.DB()
+	rs := &rates{Bulls: []*rate{}, Technicians: []*rate{}}
+
+	results, err := db.Query(bullRates+where+
+		" GROUP BY i.bull, i.external_bull, a.name, e.name ORDER BY 4 DESC", args...)
+	if err != nil {
+		return nil, api.Internal(err)
+	}
+	defer results.Close()
+	for results.Next() {
+		r := &rate{Bull: new(int)}
+		if err := results.Scan(r.Bull, &r.External, &r.Name, &r.Services, &r.Conceived); err != nil {
+			return nil, api.Internal(err)
+		}
+		r.Rate = float64(r.Conceived) / float64(r.Services)
+		rs.Bulls = append(rs.Bulls, r)
+	}
+	if err := results.Err(); err != nil {
+		return nil, api.Internal(err)
+	}
+
+	results, err = db.Query(technicianRates+where+" GROUP BY i.technician ORDER BY 2 DESC", args...)
+	if err != nil {
+		return nil, api.Internal(err)
+	}
+	defer results.Close()
+	for results.Next() {
+		r := &rate{Technician: new(string)}
+		if err := results.Scan(r.Technician, &r.Services, &r.Conceived); err != nil {
+			return nil, api.Internal(err)
+		}
+		r.Rate = float64(r.Conceived) / float64(r.Services)
+		rs.Technicians = append(rs.Technicians, r)
+	}
+	if err := results.Err(); err != nil {
+		return nil, api.Internal(err)
+	}
+	return rs, nil
+}
@@ -0,0 +1,35 @@
+package lookup
+
+import (
+	"fazendadojuca.com.br/internal/api"
+	"fazendadojuca.com.br/internal/repository"
+)
+
+type breed struct {
+	ID   int    `json:"id,omitempty"`
+	Name string `json:"name"`
+	// GestationDays is null for the usual 283 days.
+	GestationDays *int `json:"gestation_days"`
+	// Breeds of the same group, such as the Angus varieties, pass purity on to
+	// each other. An unknown breed never does.
+	Group   *string `json:"breed_group"`
+	Unknown bool    `json:"unknown"`
+	api.Meta
+}
+
+var breedRepo = repository.New(repository.Entity[breed]{
+	Name:    "Breed",
+	Table:   "breed",
+	Columns: []string{"name", "gestation_days", "breed_group", "unknown"},
+	ID:      func(b *breed) *int { return &b.ID },
+	Fields: func(b *breed) []interface{} {
+		return []interface{}{&b.Name, &b.GestationDays, &b.Group, &b.Unknown}
+	},
+	References: []repository.Reference{
+		{Table: "animal", Column: "breed_id"},
+		{Table: "external_parent", Column: "breed_id"},
+	},
+})
+
+// BreedHandler is the API Gateway handler of the breed function.
+var BreedHandler = api.Handler[breed](breedRepo)
@@ -0,0 +1,41 @@
+package lookup
+
+import (
+	"fazendadojuca.com.br/internal/api"
+	"fazendadojuca.com.br/internal/repository"
+	"fazendadojuca.com.br/internal/validation"
+)
+
+// Sexes of a gender, which tell sires and dams apart.
+const (
+	SexMale   = "male"
+	SexFemale = "female"
+)
+
+type gender struct {
+	ID   int    `json:"id,omitempty"`
+	Name string `json:"name"`
+	// Sex is SexMale or SexFemale, null for a gender that is neither.
+	Sex *string `json:"sex"`
+	api.Meta
+}
+
+var genderRepo = repository.New(repository.Entity[gender]{
+	Name:       "Gender",
+	Table:      "gender",
+	Columns:    []string{"name", "sex"},
+	ID:         func(g *gender) *int { return &g.ID },
+	Fields:     func(g *gender) []interface{} { return []interface{}{&g.Name, &g.Sex} },
+	References: []repository.Reference{{Table: "animal", Column: "gender_id"}},
+	Validate:   validateGender,
+})
+
+func validateGender(g *gender) error {
+	if g.Sex != nil && !validation.OneOf(*g.Sex, []string{SexMale, SexFemale}) {
+		return api.Validation("Invalid Sex")
+	}
+	return nil
+}
+
+// GenderHandler is the API Gateway handler of the gender function.
+var GenderHandler = api.Handler[gender](genderRepo)
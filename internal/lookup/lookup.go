@@ -0,0 +1,4 @@
+// Package lookup defines the small lookup tables referenced by animal: breed,
+// gender and purity_level. Each one is a repository.Entity served by the
+// generic api.Handler.
+package lookup
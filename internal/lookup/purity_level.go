@@ -0,0 +1,41 @@
+package lookup
+
+import (
+	"strings"
+
+	"fazendadojuca.com.br/internal/api"
+	"fazendadojuca.com.br/internal/repository"
+	"fazendadojuca.com.br/internal/validation"
+)
+
+type purityLevel struct {
+	ID    int    `json:"id,omitempty"`
+	Level string `json:"level"`
+	api.Meta
+}
+
+var purityLevelRepo = repository.New(repository.Entity[purityLevel]{
+	Name:    "Purity Level",
+	Table:   "purity_level",
+	Columns: []string{"level"},
+	ID:      func(p *purityLevel) *int { return &p.ID },
+	Fields:  func(p *purityLevel) []interface{} { return []interface{}{&p.Level} },
+	References: []repository.Reference{
+		{Table: "animal", Column: "purity_level_id"},
+		{Table: "external_parent", Column: "purity_level_id"},
+	},
+	Validate: validatePurityLevel,
+})
+
+// validatePurityLevel requires a level that purity calculations can read: a
+// fraction between 0 and 1, such as "1" or "7/8".
+func validatePurityLevel(p *purityLevel) error {
+	p.Level = strings.TrimSpace(p.Level)
+	if _, ok := validation.Fraction(p.Level); !ok {
+		return api.Validation("Invalid Level")
+	}
+	return nil
+}
+
+// PurityLevelHandler is the API Gateway handler of the purity function.
+var PurityLevelHandler = api.Handler[purityLevel](purityLevelRepo)
@@ -0,0 +1,367 @@
+// Package migrate applies the numbered schema migrations and records them in
+// the schema_migrations table.
+package migrate
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+const createTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+  version INT NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  checksum CHAR(64) NOT NULL,
+  applied_at DATETIME NOT NULL,
+  PRIMARY KEY (version))`
+
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// Load reads the NNNN_name.up.sql and NNNN_name.down.sql files of fsys,
+// ordered by version. Every version needs both files.
+func Load(fsys fs.FS) ([]*Migration, error) {
+	files, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int]*Migration{}
+	for _, f := range files {
+		m := fileName.FindStringSubmatch(f.Name())
+		if m == nil {
+			continue
+		}
+		version, _ := strconv.Atoi(m[1])
+		body, err := fs.ReadFile(fsys, f.Name())
+		if err != nil {
+			return nil, err
+		}
+		mg, ok := byVersion[version]
+		if !ok {
+			mg = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mg
+		}
+		if mg.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, mg.Name, m[2])
+		}
+		if m[3] == "up" {
+			mg.Up = string(body)
+			sum := sha256.Sum256(body)
+			mg.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			mg.Down = string(body)
+		}
+	}
+	ms := []*Migration{}
+	for _, mg := range byVersion {
+		if mg.Up == "" || mg.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", mg.Version, mg.Name)
+		}
+		ms = append(ms, mg)
+	}
+	sort.Slice(ms, func(i, j int) bool { return ms[i].Version < ms[j].Version })
+	return ms, nil
+}
+
+// Statements splits a migration into its statements. A statement ends with a
+// semicolon at the end of a line; lines starting with -- are comments.
+func Statements(script string) []string {
+	var stmts []string
+	var cur strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		cur.WriteString(line)
+		cur.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			stmts = append(stmts, strings.TrimSuffix(strings.TrimSpace(cur.String()), ";"))
+			cur.Reset()
+		}
+	}
+	if s := strings.TrimSpace(cur.String()); s != "" {
+		stmts = append(stmts, s)
+	}
+	return stmts
+}
+
+type applied struct {
+	Version   int
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Runner applies Migrations to DB. With DryRun set it only prints the
+// statements it would run to Out.
+type Runner struct {
+	DB         *sql.DB
+	Migrations []*Migration
+	Out        io.Writer
+	DryRun     bool
+}
+
+func (r *Runner) applied() (map[int]*applied, error) {
+	if !r.DryRun {
+		if _, err := r.DB.Exec(createTable); err != nil {
+			return nil, err
+		}
+	}
+	rows, err := r.DB.Query("SELECT version, name, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		if r.DryRun {
+			// The table is only created on the first real run.
+			return map[int]*applied{}, nil
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	as := map[int]*applied{}
+	for rows.Next() {
+		a := new(applied)
+		var at interface{}
+		if err := rows.Scan(&a.Version, &a.Name, &a.Checksum, &at); err != nil {
+			return nil, err
+		}
+		a.AppliedAt = parseTime(at)
+		as[a.Version] = a
+	}
+	return as, rows.Err()
+}
+
+// parseTime reads applied_at whether or not the driver parses DATETIME columns.
+func parseTime(v interface{}) time.Time {
+	switch v := v.(type) {
+	case time.Time:
+		return v
+	case []byte:
+		t, _ := time.Parse("2006-01-02 15:04:05", string(v))
+		return t
+	case string:
+		t, _ := time.Parse("2006-01-02 15:04:05", v)
+		return t
+	}
+	return time.Time{}
+}
+
+// verify is the checksum guard: a migration must not change once applied.
+func (r *Runner) verify(as map[int]*applied) error {
+	known := map[int]*Migration{}
+	for _, m := range r.Migrations {
+		known[m.Version] = m
+	}
+	for v, a := range as {
+		m, ok := known[v]
+		if !ok {
+			return fmt.Errorf("migration %d_%s is applied but its files are missing", v, a.Name)
+		}
+		if m.Checksum != a.Checksum {
+			return fmt.Errorf("migration %d_%s was modified after it was applied", v, m.Name)
+		}
+	}
+	return nil
+}
+
+func (r *Runner) state() (map[int]*applied, error) {
+	as, err := r.applied()
+	if err != nil {
+		return nil, err
+	}
+	return as, r.verify(as)
+}
+
+// execer is implemented by both *sql.DB and *sql.Tx.
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// isPragma reports whether stmt is an SQLite PRAGMA, which does nothing within
+// a transaction.
+func isPragma(stmt string) bool {
+	return strings.HasPrefix(strings.ToUpper(stmt), "PRAGMA ")
+}
+
+// exec runs script in one transaction along with done, which records or
+// forgets the migration, so a failed migration leaves nothing behind. The
+// PRAGMAs leading and trailing script, such as the foreign_keys switch around
+// SQLite table rebuilds, run on the connection before and after it; with them,
+// the foreign keys are checked before committing. MySQL commits each DDL
+// statement as it runs, so there only the data changes are undone.
+func (r *Runner) exec(script string, done func(tx execer) error) error {
+	stmts := Statements(script)
+	if r.DryRun {
+		for _, stmt := range stmts {
+			fmt.Fprintf(r.Out, "%s;\n\n", stmt)
+		}
+		return nil
+	}
+	var before, after []string
+	for len(stmts) > 0 && isPragma(stmts[0]) {
+		before, stmts = append(before, stmts[0]), stmts[1:]
+	}
+	for len(stmts) > 0 && isPragma(stmts[len(stmts)-1]) {
+		after, stmts = append([]string{stmts[len(stmts)-1]}, after...), stmts[:len(stmts)-1]
+	}
+	for _, stmt := range before {
+		if _, err := r.DB.Exec(stmt); err != nil {
+			return fmt.Errorf("%w\n%s", err, stmt)
+		}
+	}
+	err := r.inTx(func(tx *sql.Tx) error {
+		for _, stmt := range stmts {
+			if _, err := tx.Exec(stmt); err != nil {
+				return fmt.Errorf("%w\n%s", err, stmt)
+			}
+		}
+		if len(before) > 0 {
+			if err := checkForeignKeys(tx); err != nil {
+				return err
+			}
+		}
+		return done(tx)
+	})
+	for _, stmt := range after {
+		if _, afterErr := r.DB.Exec(stmt); afterErr != nil && err == nil {
+			err = fmt.Errorf("%w\n%s", afterErr, stmt)
+		}
+	}
+	return err
+}
+
+// inTx runs fn in a transaction on r.DB, committed when fn returns nil and
+// rolled back otherwise.
+func (r *Runner) inTx(fn func(tx *sql.Tx) error) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// checkForeignKeys fails when a row points to one that does not exist, as
+// SQLite lets through while its foreign keys are off.
+func checkForeignKeys(tx *sql.Tx) error {
+	rows, err := tx.Query("PRAGMA foreign_key_check")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		var table, parent string
+		var rowid sql.NullInt64
+		var fk int
+		if err := rows.Scan(&table, &rowid, &parent, &fk); err != nil {
+			return err
+		}
+		return fmt.Errorf("row %d of %s points to a missing %s", rowid.Int64, table, parent)
+	}
+	return rows.Err()
+}
+
+func (r *Runner) record(q execer, m *Migration) error {
+	if r.DryRun {
+		return nil
+	}
+	_, err := q.Exec(
+		"INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)",
+		m.Version, m.Name, m.Checksum, time.Now().UTC().Format("2006-01-02 15:04:05"))
+	return err
+}
+
+// Up applies every pending migration up to version to, or all of them when to is 0.
+func (r *Runner) Up(to int) error {
+	as, err := r.state()
+	if err != nil {
+		return err
+	}
+	for _, m := range r.Migrations {
+		if as[m.Version] != nil || (to > 0 && m.Version > to) {
+			continue
+		}
+		fmt.Fprintf(r.Out, "-- up %04d_%s\n", m.Version, m.Name)
+		err := r.exec(m.Up, func(tx execer) error { return r.record(tx, m) })
+		if err != nil {
+			return fmt.Errorf("migration %d_%s: %w", m.Version, m.Name, err)
+		}
+	}
+	return nil
+}
+
+// Down reverts the last steps applied migrations.
+func (r *Runner) Down(steps int) error {
+	as, err := r.state()
+	if err != nil {
+		return err
+	}
+	for i := len(r.Migrations) - 1; i >= 0 && steps > 0; i-- {
+		m := r.Migrations[i]
+		if as[m.Version] == nil {
+			continue
+		}
+		steps--
+		fmt.Fprintf(r.Out, "-- down %04d_%s\n", m.Version, m.Name)
+		err := r.exec(m.Down, func(tx execer) error {
+			_, err := tx.Exec("DELETE FROM schema_migrations WHERE version = ?", m.Version)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d_%s: %w", m.Version, m.Name, err)
+		}
+	}
+	return nil
+}
+
+// Baseline records the migrations up to version to as applied without running
+// them, for databases that were created from model.sql.
+func (r *Runner) Baseline(to int) error {
+	as, err := r.state()
+	if err != nil {
+		return err
+	}
+	for _, m := range r.Migrations {
+		if as[m.Version] != nil || m.Version > to {
+			continue
+		}
+		fmt.Fprintf(r.Out, "-- baseline %04d_%s\n", m.Version, m.Name)
+		if err := r.record(r.DB, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Status prints every migration and when it was applied.
+func (r *Runner) Status() error {
+	as, err := r.state()
+	if err != nil {
+		return err
+	}
+	for _, m := range r.Migrations {
+		when := "pending"
+		if a := as[m.Version]; a != nil {
+			when = a.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(r.Out, "%04d_%-30s %s\n", m.Version, m.Name, when)
+	}
+	return nil
+}
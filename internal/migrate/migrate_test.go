@@ -0,0 +1,127 @@
+package migrate_test
+
+import (
+	"database/sql"
+	"io"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	_ "modernc.org/sqlite"
+
+	"fazendadojuca.com.br/internal/migrate"
+	"fazendadojuca.com.br/migrations"
+)
+
+// open returns an SQLite database configured as the sqlite backend opens it.
+func open(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := "file:" + filepath.Join(t.TempDir(), "test.db") + "?_pragma=foreign_keys(1)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func tables(t *testing.T, db *sql.DB) []string {
+	t.Helper()
+	rows, err := db.Query(`SELECT name FROM sqlite_master
+	WHERE type = 'table' AND name NOT IN ('schema_migrations', 'sqlite_sequence')
+	ORDER BY name`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// herd fills every table with rows referencing each other.
+var herd = []string{
+	`INSERT INTO external_parent (id, name, registry, breed_id, purity_level_id) VALUES (1, 'Semen Bull', 'R1', 2, 1)`,
+	`INSERT INTO animal (ID, name, number, registry, origin, insemination, birth, gender_id, breed_id, purity_level_id)
+	VALUES (1, 'Bull', '', '', '', 0, '2018-01-01', 1, 2, 1)`,
+	`INSERT INTO animal (ID, name, number, registry, origin, insemination, birth, gender_id, breed_id, purity_level_id)
+	VALUES (2, 'Cow', '', '', '', 0, '2018-01-01', 2, 2, 2)`,
+	`INSERT INTO insemination (id, date, cow, external_bull, technician, straw_batch, outcome)
+	VALUES (1, '2020-01-01', 2, 1, '', '', 'calved')`,
+	`INSERT INTO animal (ID, name, number, registry, origin, father, mother, external_father, insemination,
+	insemination_id, birth, gender_id, breed_id, purity_level_id, deleted_at)
+	VALUES (3, 'Calf', '', '', '', NULL, 2, 1, 1, 1, '2020-10-01', 2, 2, 3, '2024-01-01 00:00:00')`,
+	`INSERT INTO animal (ID, name, number, registry, origin, father, mother, insemination, birth, gender_id,
+	breed_id, purity_level_id)
+	VALUES (4, 'Grandcalf', '', '', '', 1, 3, 0, '2023-10-01', 1, 2, 4)`,
+	`INSERT INTO reproduction_event (cow, date, type, method, bull, insemination_id, calf, notes)
+	VALUES (3, '2023-01-01', 'service', 'natural', 1, NULL, NULL, '')`,
+	`INSERT INTO reproduction_event (cow, date, type, calving_outcome, calf, notes)
+	VALUES (3, '2023-10-01', 'calving', 'live', 4, '')`,
+	`INSERT INTO weighing (animal, date, weight, method) VALUES (4, '2023-10-01', 35, 'scale')`,
+	`INSERT INTO health_event (animal, date, type, product, dose, route, vet, notes)
+	VALUES (4, '2023-11-01', 'vaccination', 'Aftosa', '5ml', 'im', '', '')`,
+	`INSERT INTO audit_log (actor, claimed_actor, entity, entity_id, action, changes)
+	VALUES ('anonymous', 'maria', 'animal', 4, 'create', '{}')`,
+}
+
+func TestUpDownPopulated(t *testing.T) {
+	db := open(t)
+	ms, err := migrate.Load(migrations.For("sqlite"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &migrate.Runner{DB: db, Migrations: ms, Out: io.Discard}
+	if err := r.Up(0); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	for _, stmt := range herd {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("%v\n%s", err, stmt)
+		}
+	}
+
+	if err := r.Down(len(ms)); err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+	if names := tables(t, db); len(names) != 0 {
+		t.Errorf("tables left after Down: %v", names)
+	}
+	var fk int
+	if err := db.QueryRow("PRAGMA foreign_keys").Scan(&fk); err != nil || fk != 1 {
+		t.Errorf("foreign_keys = %d (%v) after Down, want 1", fk, err)
+	}
+
+	if err := r.Up(0); err != nil {
+		t.Fatalf("Up again: %v", err)
+	}
+}
+
+func TestFailedMigrationLeavesNothing(t *testing.T) {
+	db := open(t)
+	ms, err := migrate.Load(fstest.MapFS{
+		"0001_broken.up.sql":   {Data: []byte("CREATE TABLE first (id INT);\n\nINSERT INTO missing VALUES (1);\n")},
+		"0001_broken.down.sql": {Data: []byte("DROP TABLE first;\n")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &migrate.Runner{DB: db, Migrations: ms, Out: io.Discard}
+	if err := r.Up(0); err == nil {
+		t.Fatal("Up of a broken migration succeeded")
+	}
+	if names := tables(t, db); len(names) != 0 {
+		t.Errorf("tables left by the broken migration: %v", names)
+	}
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&n); err != nil || n != 0 {
+		t.Errorf("schema_migrations has %d rows (%v), want 0", n, err)
+	}
+}
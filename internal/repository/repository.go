@@ -0,0 +1,271 @@
+// Package repository implements the CRUD service of api.Service for entities
+// stored in a single table with an integer id primary key.
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"fazendadojuca.com.br/internal/api"
+	"fazendadojuca.com.br/internal/audit"
+	"fazendadojuca.com.br/internal/database"
+)
+
+// Entity describes how T maps onto its table. T must embed api.Meta, which is
+// stored in the version and updated_at columns every table has.
+type Entity[T any] struct {
+	// Name is used in error messages, e.g. "Breed Not Found".
+	Name    string
+	Table   string
+	Columns []string
+	// ID returns a pointer to the id field of v.
+	ID func(v *T) *int
+	// Fields returns pointers to the fields of v in Columns order.
+	Fields func(v *T) []interface{}
+	// References lists the columns of other tables pointing to this entity.
+	// Delete refuses to remove a row that is still referenced.
+	References []Reference
+	// Validate, when set, checks v before Create and Update write it. It may
+	// also normalize v, e.g. trim strings or fill in defaults.
+	Validate func(v *T) error
+}
+
+// Reference is a foreign key column of another table. The referencing table
+// must have id and name columns, which are reported in the conflict.
+type Reference struct {
+	Table  string
+	Column string
+}
+
+// Referrer is a row that references the entity being deleted.
+type Referrer struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type Repository[T any] struct {
+	Entity[T]
+	selectSQL string
+}
+
+func New[T any](e Entity[T]) *Repository[T] {
+	return &Repository[T]{
+		Entity:    e,
+		selectSQL: fmt.Sprintf("SELECT id, version, updated_at, %s FROM %s", strings.Join(e.Columns, ", "), e.Table),
+	}
+}
+
+func meta(v interface{}) *api.Meta {
+	return v.(api.Versioned).Metadata()
+}
+
+func (r *Repository[T]) scan(s database.Scanner) (*T, error) {
+	v := new(T)
+	m := meta(v)
+	err := s.Scan(append([]interface{}{r.ID(v), &m.Version, &m.UpdatedAt}, r.Fields(v)...)...)
+	return v, err
+}
+
+func (r *Repository[T]) notFound() error {
+	return api.NotFound(r.Name + " Not Found")
+}
+
+func (r *Repository[T]) FetchOne(id int) (*T, error) {
+	return r.fetch(database.DB(), id, "")
+}
+
+// Lock loads the row id within tx and locks it until tx ends, so that it can
+// be changed with UpdateIn.
+func (r *Repository[T]) Lock(tx database.Querier, id int) (*T, error) {
+	return r.fetch(tx, id, database.ForUpdate())
+}
+
+func (r *Repository[T]) fetch(q database.Querier, id int, lock string) (*T, error) {
+	if id == 0 {
+		return nil, api.Validation("Invalid ID")
+	}
+	row := q.QueryRow(r.selectSQL+" WHERE id= ?"+lock, id)
+	v, err := r.scan(row)
+	if err == sql.ErrNoRows {
+		return nil, r.notFound()
+	}
+	if err != nil {
+		return nil, api.Internal(err)
+	}
+	return v, nil
+}
+
+func (r *Repository[T]) FetchAll() ([]*T, error) {
+	db := database.DB()
+	results, err := db.Query(r.selectSQL)
+	if err != nil {
+		return nil, api.Internal(err)
+	}
+	defer results.Close()
+	vs := []*T{}
+	for results.Next() {
+		v, err := r.scan(results)
+		if err != nil {
+			return nil, api.Internal(err)
+		}
+		vs = append(vs, v)
+	}
+	if err := results.Err(); err != nil {
+		return nil, api.Internal(err)
+	}
+	return vs, nil
+}
+
+// validate runs the Validate hook of the entity, if any.
+func (r *Repository[T]) validate(v *T) error {
+	if r.Validate == nil {
+		return nil
+	}
+	return r.Validate(v)
+}
+
+// Create inserts v and records it in the audit log, in one transaction.
+func (r *Repository[T]) Create(v *T, actor audit.Actor) (*T, error) {
+	if err := r.validate(v); err != nil {
+		return nil, err
+	}
+	var created *T
+	err := database.InTx(func(tx *sql.Tx) error {
+		res, err := tx.Exec(
+			fmt.Sprintf("INSERT INTO %s (%s, version, updated_at) VALUES (%s1, CURRENT_TIMESTAMP);",
+				r.Table, strings.Join(r.Columns, ", "), strings.Repeat("?, ", len(r.Columns))),
+			r.Fields(v)...)
+		if database.IsForeignKeyError(err) {
+			return api.Validation("Invalid Reference")
+		}
+		if err != nil {
+			return api.Internal(err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return api.Internal(err)
+		}
+		created, err = r.fetch(tx, int(id), "")
+		if err != nil {
+			return err
+		}
+		return r.record(tx, actor, audit.ActionCreate, nil, created)
+	})
+	return created, err
+}
+
+// Update writes v and records the change in the audit log, in one transaction.
+func (r *Repository[T]) Update(v *T, actor audit.Actor) (*T, error) {
+	if *r.ID(v) == 0 {
+		return nil, api.Validation("Invalid ID")
+	}
+	if err := r.validate(v); err != nil {
+		return nil, err
+	}
+	var updated *T
+	err := database.InTx(func(tx *sql.Tx) error {
+		var err error
+		updated, err = r.UpdateIn(tx, v, actor)
+		return err
+	})
+	return updated, err
+}
+
+// UpdateIn writes v within tx and records the change in the audit log. Unlike
+// Update, it leaves v unvalidated: Validate may query the database outside tx.
+func (r *Repository[T]) UpdateIn(tx database.Querier, v *T, actor audit.Actor) (*T, error) {
+	id := *r.ID(v)
+	before, err := r.Lock(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(before, meta(v).Version); err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(
+		fmt.Sprintf("UPDATE %s SET %s = ?, version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = ?;",
+			r.Table, strings.Join(r.Columns, " = ?, ")),
+		append(r.Fields(v), id)...)
+	if database.IsForeignKeyError(err) {
+		return nil, api.Validation("Invalid Reference")
+	}
+	if err != nil {
+		return nil, api.Internal(err)
+	}
+	after, err := r.fetch(tx, id, "")
+	if err != nil {
+		return nil, err
+	}
+	return after, r.record(tx, actor, audit.ActionUpdate, before, after)
+}
+
+// Delete removes the row id and records it in the audit log, in one transaction.
+func (r *Repository[T]) Delete(id, version int, actor audit.Actor) error {
+	return database.InTx(func(tx *sql.Tx) error {
+		before, err := r.Lock(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(before, version); err != nil {
+			return err
+		}
+		refs, err := r.referrers(tx, id)
+		if err != nil {
+			return err
+		}
+		if len(refs) > 0 {
+			e := api.Conflict(r.Name + " In Use")
+			e.Details = refs
+			return e
+		}
+		_, err = tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE id = ?", r.Table), id)
+		if database.IsForeignKeyError(err) {
+			return api.Conflict(r.Name + " In Use")
+		}
+		if err != nil {
+			return api.Internal(err)
+		}
+		return r.record(tx, actor, audit.ActionDelete, before, nil)
+	})
+}
+
+// checkVersion fails when v is no longer at version. Version 0 matches any.
+func checkVersion(v interface{}, version int) error {
+	if version != 0 && meta(v).Version != version {
+		return api.PreconditionFailed("Version Mismatch")
+	}
+	return nil
+}
+
+func (r *Repository[T]) record(tx database.Querier, actor audit.Actor, action string, before, after *T) error {
+	if err := audit.Record(tx, actor, r.Table, action, before, after); err != nil {
+		return api.Internal(err)
+	}
+	return nil
+}
+
+// referrers lists the rows still pointing to id, keyed by table.
+func (r *Repository[T]) referrers(q database.Querier, id int) (map[string][]Referrer, error) {
+	refs := map[string][]Referrer{}
+	for _, ref := range r.References {
+		results, err := q.Query(fmt.Sprintf("SELECT id, name FROM %s WHERE %s = ?", ref.Table, ref.Column), id)
+		if err != nil {
+			return nil, api.Internal(err)
+		}
+		for results.Next() {
+			var rr Referrer
+			if err := results.Scan(&rr.ID, &rr.Name); err != nil {
+				results.Close()
+				return nil, api.Internal(err)
+			}
+			refs[ref.Table] = append(refs[ref.Table], rr)
+		}
+		err = results.Err()
+		results.Close()
+		if err != nil {
+			return nil, api.Internal(err)
+		}
+	}
+	return refs, nil
+}
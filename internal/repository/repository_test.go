@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"fazendadojuca.com.br/internal/api"
+	"fazendadojuca.com.br/internal/audit"
+)
+
+type level struct {
+	ID    int    `json:"id,omitempty"`
+	Level string `json:"level"`
+	api.Meta
+}
+
+var levelRepo = New(Entity[level]{
+	Name:    "Purity Level",
+	Table:   "purity_level",
+	Columns: []string{"level"},
+	ID:      func(l *level) *int { return &l.ID },
+	Fields:  func(l *level) []interface{} { return []interface{}{&l.Level} },
+})
+
+var actor = audit.Actor{Principal: "tester"}
+
+func TestMain(m *testing.M) {
+	os.Setenv("DB_BACKEND", "memory")
+	os.Exit(m.Run())
+}
+
+func status(err error) int {
+	var e *api.Error
+	if !errors.As(err, &e) {
+		return 0
+	}
+	return e.Status
+}
+
+func TestRoundTrip(t *testing.T) {
+	created, err := levelRepo.Create(&level{Level: "63/64"}, actor)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if created.ID == 0 || created.Version != 1 {
+		t.Fatalf("Create = %+v, want an id at version 1", created)
+	}
+
+	got, err := levelRepo.FetchOne(created.ID)
+	if err != nil {
+		t.Fatalf("FetchOne: %v", err)
+	}
+	if got.Level != "63/64" {
+		t.Errorf("FetchOne level = %q, want 63/64", got.Level)
+	}
+
+	got.Level = "127/128"
+	updated, err := levelRepo.Update(got, actor)
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if updated.Level != "127/128" || updated.Version != 2 {
+		t.Errorf("Update = %+v, want 127/128 at version 2", updated)
+	}
+
+	got.Level = "stale"
+	if _, err := levelRepo.Update(got, actor); status(err) != http.StatusPreconditionFailed {
+		t.Errorf("Update of version 1 = %v, want 412", err)
+	}
+	if err := levelRepo.Delete(created.ID, 1, actor); status(err) != http.StatusPreconditionFailed {
+		t.Errorf("Delete of version 1 = %v, want 412", err)
+	}
+
+	if err := levelRepo.Delete(created.ID, 2, actor); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := levelRepo.FetchOne(created.ID); status(err) != http.StatusNotFound {
+		t.Errorf("FetchOne after Delete = %v, want 404", err)
+	}
+	if err := levelRepo.Delete(created.ID, 0, actor); status(err) != http.StatusNotFound {
+		t.Errorf("Delete of a missing row = %v, want 404", err)
+	}
+
+	es, err := audit.History("purity_level", created.ID)
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	var actions []string
+	for _, e := range es {
+		if e.Actor != actor.Principal {
+			t.Errorf("History actor = %q, want %q", e.Actor, actor.Principal)
+		}
+		actions = append(actions, e.Action)
+	}
+	want := []string{audit.ActionCreate, audit.ActionUpdate, audit.ActionDelete}
+	if strings.Join(actions, " ") != strings.Join(want, " ") {
+		t.Errorf("History actions = %v, want %v", actions, want)
+	}
+}
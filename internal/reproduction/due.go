@@ -0,0 +1,96 @@
+package reproduction
+
+import (
+	"net/http"
+
+	"fazendadojuca.com.br/internal/api"
+	"fazendadojuca.com.br/internal/database"
+	"fazendadojuca.com.br/internal/date"
+)
+
+const (
+	defaultDueDays = 30
+	maxDueDays     = 365
+)
+
+// dueCow is a cow whose last service has not been followed by a calving, a
+// negative pregnancy check or another service.
+type dueCow struct {
+	Cow             int       `json:"cow"`
+	Name            string    `json:"name"`
+	Number          string    `json:"number"`
+	Breed           string    `json:"breed"`
+	Service         int       `json:"service"`
+	ServiceDate     date.Date `json:"service_date"`
+	ExpectedCalving date.Date `json:"expected_calving"`
+	// Days until the expected calving, negative when overdue.
+	Days      int  `json:"days"`
+	Confirmed bool `json:"confirmed"`
+}
+
+// dueSelect lists the open services expected to calve by a given date. A
+// service is closed by any later calving, negative pregnancy check or service;
+// of events on the same day, the one recorded last is the later.
+const dueSelect = `
+	SELECT
+		a.id,
+		a.name,
+		a.number,
+		b.name,
+		s.id,
+		s.date,
+		s.expected_calving,
+		EXISTS (
+			SELECT 1 FROM reproduction_event p
+			WHERE p.cow = s.cow AND p.date >= s.date AND p.type = '` + TypePregnancyCheck + `' AND p.pregnant = 1)
+	FROM reproduction_event s
+		JOIN animal a ON a.id = s.cow
+		JOIN breed b ON b.id = a.breed_id
+	WHERE s.type = '` + TypeService + `'
+		AND s.expected_calving <= ?
+		AND a.death IS NULL
+		AND a.deleted_at IS NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM reproduction_event c
+			WHERE c.cow = s.cow AND (c.date > s.date OR (c.date = s.date AND c.id > s.id))
+				AND (c.type IN ('` + TypeCalving + `', '` + TypeService + `')
+					OR (c.type = '` + TypePregnancyCheck + `' AND c.pregnant = 0)))
+	ORDER BY s.expected_calving, a.id`
+
+func getDue(req api.Request) (*api.Response, error) {
+	days, err := api.QueryInt(req, "days", defaultDueDays, maxDueDays)
+	if err != nil {
+		return api.Fail(err)
+	}
+	result, err := serviceDue(days)
+	if err != nil {
+		return api.Fail(err)
+	}
+	return api.JSON(http.StatusOK, result)
+}
+
+// serviceDue lists the cows expected to calve within days from today, overdue
+// ones included.
+func serviceDue(days int) ([]*dueCow, error) {
+	today := date.Today()
+	db := database.DB()
+	results, err := db.Query(dueSelect, date.Of(today.AddDate(0, 0, days)))
+	if err != nil {
+		return nil, api.Internal(err)
+	}
+	defer results.Close()
+	cows := []*dueCow{}
+	for results.Next() {
+		c := new(dueCow)
+		err := results.Scan(&c.Cow, &c.Name, &c.Number, &c.Breed, &c.Service, &c.ServiceDate, &c.ExpectedCalving, &c.Confirmed)
+		if err != nil {
+			return nil, api.Internal(err)
+		}
+		c.Days = int(c.ExpectedCalving.Sub(today.Time).Hours() / 24)
+		cows = append(cows, c)
+	}
+	if err := results.Err(); err != nil {
+		return nil, api.Internal(err)
+	}
+	return cows, nil
+}
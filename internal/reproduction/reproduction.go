@@ -0,0 +1,172 @@
+// Package reproduction implements the reproduction function: the event log of
+// each cow (heat, service, pregnancy check and calving) and the list of cows
+// due to calve.
+package reproduction
+
+import (
+	"database/sql"
+	"strings"
+
+	"fazendadojuca.com.br/internal/api"
+	"fazendadojuca.com.br/internal/database"
+	"fazendadojuca.com.br/internal/date"
+	"fazendadojuca.com.br/internal/repository"
+	"fazendadojuca.com.br/internal/validation"
+)
+
+// Event types.
+const (
+	TypeHeat           = "heat"
+	TypeService        = "service"
+	TypePregnancyCheck = "pregnancy_check"
+	TypeCalving        = "calving"
+)
+
+// Service methods.
+const (
+	MethodNatural = "natural"
+	MethodAI      = "ai"
+)
+
+var calvingOutcomes = []string{"live", "stillborn", "abortion"}
+
+// defaultGestationDays applies to breeds without gestation_days.
+const defaultGestationDays = 283
+
+// event is a single reproduction event of cow. Which of the optional fields
+// apply depends on Type:
+//   - service: Method, and the bull as Bull, ExternalBull or the one of
+//     InseminationID. ExpectedCalving is derived from the gestation of the
+//     breed of cow.
+//   - pregnancy_check: Pregnant.
+//   - calving: CalvingOutcome and, when it was registered, Calf.
+type event struct {
+	ID              int        `json:"id,omitempty"`
+	Cow             int        `json:"cow"`
+	Date            date.Date  `json:"date"`
+	Type            string     `json:"type"`
+	Method          *string    `json:"method,omitempty"`
+	Bull            *int       `json:"bull,omitempty"`
+	ExternalBull    *int       `json:"external_bull,omitempty"`
+	InseminationID  *int       `json:"insemination_id,omitempty"`
+	ExpectedCalving *date.Date `json:"expected_calving,omitempty"`
+	Pregnant        *bool      `json:"pregnant,omitempty"`
+	CalvingOutcome  *string    `json:"calving_outcome,omitempty"`
+	Calf            *int       `json:"calf,omitempty"`
+	Notes           string     `json:"notes"`
+	api.Meta
+}
+
+var eventRepo = repository.New(repository.Entity[event]{
+	Name:  "Reproduction Event",
+	Table: "reproduction_event",
+	Columns: []string{"cow", "date", "type", "method", "bull", "external_bull", "insemination_id",
+		"expected_calving", "pregnant", "calving_outcome", "calf", "notes"},
+	ID: func(e *event) *int { return &e.ID },
+	Fields: func(e *event) []interface{} {
+		return []interface{}{&e.Cow, &e.Date, &e.Type, &e.Method, &e.Bull, &e.ExternalBull, &e.InseminationID,
+			&e.ExpectedCalving, &e.Pregnant, &e.CalvingOutcome, &e.Calf, &e.Notes}
+	},
+	Validate: prepare,
+})
+
+func prepare(e *event) error {
+	if e.Cow == 0 {
+		return api.Validation("Cow Required")
+	}
+	if e.Date.IsZero() {
+		return api.Validation("Invalid Date")
+	}
+	if e.Date.After(date.Today().Time) {
+		return api.Validation("Date In The Future")
+	}
+	e.Notes = strings.TrimSpace(e.Notes)
+	// Fields of other event types are dropped rather than stored.
+	kept := event{ID: e.ID, Cow: e.Cow, Date: e.Date, Type: e.Type, Notes: e.Notes, Meta: e.Meta}
+	switch e.Type {
+	case TypeHeat:
+	case TypeService:
+		kept.Method, kept.Bull, kept.ExternalBull, kept.InseminationID = e.Method, e.Bull, e.ExternalBull, e.InseminationID
+		if err := prepareService(&kept); err != nil {
+			return err
+		}
+	case TypePregnancyCheck:
+		if e.Pregnant == nil {
+			return api.Validation("Pregnant Required")
+		}
+		kept.Pregnant = e.Pregnant
+	case TypeCalving:
+		if e.CalvingOutcome == nil || !validation.OneOf(*e.CalvingOutcome, calvingOutcomes) {
+			return api.Validation("Invalid Calving Outcome")
+		}
+		kept.CalvingOutcome, kept.Calf = e.CalvingOutcome, e.Calf
+	default:
+		return api.Validation("Invalid Type")
+	}
+	*e = kept
+	return nil
+}
+
+func prepareService(e *event) error {
+	if e.InseminationID != nil {
+		var cow int
+		db := database.DB()
+		row := db.QueryRow("SELECT cow, bull, external_bull FROM insemination WHERE id = ?", *e.InseminationID)
+		err := row.Scan(&cow, &e.Bull, &e.ExternalBull)
+		if err == sql.ErrNoRows {
+			return api.Validation("Insemination Not Found")
+		}
+		if err != nil {
+			return api.Internal(err)
+		}
+		if cow != e.Cow {
+			return api.Validation("Cow Does Not Match Insemination")
+		}
+		method := MethodAI
+		e.Method = &method
+	}
+	if e.Method == nil || (*e.Method != MethodNatural && *e.Method != MethodAI) {
+		return api.Validation("Invalid Method")
+	}
+	if (e.Bull == nil) == (e.ExternalBull == nil) {
+		return api.Validation("Either Bull Or External Bull Required")
+	}
+	days, err := gestationDays(e.Cow)
+	if err != nil {
+		return err
+	}
+	expected := date.Of(e.Date.AddDate(0, 0, days))
+	e.ExpectedCalving = &expected
+	return nil
+}
+
+// gestationDays returns the gestation length of the breed of cow.
+func gestationDays(cow int) (int, error) {
+	var days int
+	db := database.DB()
+	row := db.QueryRow(`
+	SELECT IFNULL(b.gestation_days, ?)
+	FROM animal a
+		JOIN breed b ON b.id = a.breed_id
+	WHERE a.id = ?`, defaultGestationDays, cow)
+	err := row.Scan(&days)
+	if err == sql.ErrNoRows {
+		return 0, api.Validation("Cow Not Found")
+	}
+	if err != nil {
+		return 0, api.Internal(err)
+	}
+	return days, nil
+}
+
+var crud = api.Handler[event](eventRepo)
+
+// Handler is the API Gateway handler of the reproduction function.
+var Handler = api.Recover(handler)
+
+func handler(req api.Request) (*api.Response, error) {
+	if req.HTTPMethod == "GET" && strings.HasSuffix(req.Path, "/due") {
+		return getDue(req)
+	}
+	return crud(req)
+}
@@ -0,0 +1,91 @@
+// Package validation collects the field-level errors of a payload so that all
+// of them are reported at once, as
+//
+//	{"errors":[{"field":"mother","code":"wrong_gender"}]}
+package validation
+
+import (
+	"math/big"
+	"strings"
+
+	"fazendadojuca.com.br/internal/api"
+)
+
+// Codes of a field error.
+const (
+	Required             = "required"
+	TooLong              = "too_long"
+	NotFound             = "not_found"
+	WrongGender          = "wrong_gender"
+	YoungerThanCalf      = "younger_than_calf"
+	SelfReference        = "self_reference"
+	InFuture             = "in_future"
+	BeforeBirth          = "before_birth"
+	Conflict             = "conflict"
+	InseminationMismatch = "insemination_mismatch"
+)
+
+// Errors accumulates field errors. The zero value is ready to use.
+type Errors struct {
+	fields []api.FieldError
+}
+
+// Add records that field failed with code. Only the first error of each
+// field is kept, later rules usually depend on the earlier ones passing.
+func (e *Errors) Add(field, code string) {
+	if e.Has(field) {
+		return
+	}
+	e.fields = append(e.fields, api.FieldError{Field: field, Code: code})
+}
+
+// Check adds the error when ok is false.
+func (e *Errors) Check(ok bool, field, code string) {
+	if !ok {
+		e.Add(field, code)
+	}
+}
+
+// MaxLen checks that s is at most n characters long.
+func (e *Errors) MaxLen(s string, n int, field string) {
+	e.Check(len([]rune(s)) <= n, field, TooLong)
+}
+
+// OneOf reports whether v is one of the allowed values vs.
+func OneOf(v string, vs []string) bool {
+	for _, s := range vs {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Fraction parses a purity level such as "1" or "7/8", which must lie between
+// 0 and 1.
+func Fraction(level string) (*big.Rat, bool) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(level))
+	if !ok || r.Sign() < 0 || r.Cmp(big.NewRat(1, 1)) > 0 {
+		return nil, false
+	}
+	return r, true
+}
+
+// Has reports whether field already failed.
+func (e *Errors) Has(field string) bool {
+	for _, f := range e.fields {
+		if f.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Err returns the collected errors as an api.Invalid error, or nil if there
+// are none.
+func (e *Errors) Err() error {
+	if len(e.fields) == 0 {
+		return nil
+	}
+	return api.Invalid(e.fields)
+}
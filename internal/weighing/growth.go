@@ -0,0 +1,206 @@
+package weighing
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"fazendadojuca.com.br/internal/api"
+	"fazendadojuca.com.br/internal/database"
+	"fazendadojuca.com.br/internal/date"
+)
+
+// Adjusted 205-day weaning weight, as defined by the Beef Improvement
+// Federation: ((weaning - birth weight) / age * 205) + birth weight, for calves
+// weaned between 160 and 250 days old. Calves without a birth weighing use
+// defaultBirthWeight.
+const (
+	adjustedAge        = 205
+	minWeaningAge      = 160
+	maxWeaningAge      = 250
+	defaultBirthWeight = 35.0
+)
+
+// growth holds the figures of a single animal. ADG is the average daily gain
+// in kg/day between its first and last weighings.
+type growth struct {
+	Animal               int      `json:"animal"`
+	Name                 string   `json:"name"`
+	Weighings            int      `json:"weighings"`
+	ADG                  *float64 `json:"adg"`
+	WeaningAge           *int     `json:"weaning_age,omitempty"`
+	Adjusted205          *float64 `json:"adjusted_205"`
+	BirthWeightEstimated bool     `json:"birth_weight_estimated,omitempty"`
+}
+
+// growthGroup gathers the animals of one breed and purity level.
+type growthGroup struct {
+	Breed          string    `json:"breed"`
+	PurityLevel    string    `json:"purity_level"`
+	Animals        []*growth `json:"animals"`
+	AvgADG         *float64  `json:"avg_adg"`
+	AvgAdjusted205 *float64  `json:"avg_adjusted_205"`
+}
+
+const growthSelect = `
+	SELECT
+		a.id,
+		a.name,
+		a.birth,
+		b.name,
+		p.level,
+		w.date,
+		w.weight,
+		w.stage
+	FROM weighing w
+		JOIN animal a ON a.id = w.animal
+		JOIN breed b ON b.id = a.breed_id
+		JOIN purity_level p ON p.id = a.purity_level_id`
+
+type growthRow struct {
+	animal      int
+	name        string
+	birth       date.Date
+	breed       string
+	purityLevel string
+	date        date.Date
+	weight      float64
+	stage       *string
+}
+
+func getGrowth(req api.Request) (*api.Response, error) {
+	where, args := "", []interface{}{}
+	for _, f := range []struct{ param, column string }{
+		{"animal", "a.id"},
+		{"breed_id", "a.breed_id"},
+		{"purity_level_id", "a.purity_level_id"},
+	} {
+		v := req.QueryStringParameters[f.param]
+		if v == "" {
+			continue
+		}
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return api.Fail(api.Validation("Invalid " + f.param))
+		}
+		if where == "" {
+			where = " WHERE "
+		} else {
+			where += " AND "
+		}
+		where += f.column + " = ?"
+		args = append(args, id)
+	}
+	result, err := serviceGrowth(where, args)
+	if err != nil {
+		return api.Fail(err)
+	}
+	return api.JSON(http.StatusOK, result)
+}
+
+func serviceGrowth(where string, args []interface{}) ([]*growthGroup, error) {
+	db := database.DB()
+	results, err := db.Query(growthSelect+where+" ORDER BY b.name, p.level, a.id, w.date, w.id", args...)
+	if err != nil {
+		return nil, api.Internal(err)
+	}
+	defer results.Close()
+	rows := []*growthRow{}
+	for results.Next() {
+		r := new(growthRow)
+		err := results.Scan(&r.animal, &r.name, &r.birth, &r.breed, &r.purityLevel, &r.date, &r.weight, &r.stage)
+		if err != nil {
+			return nil, api.Internal(err)
+		}
+		rows = append(rows, r)
+	}
+	if err := results.Err(); err != nil {
+		return nil, api.Internal(err)
+	}
+	// Rows come sorted by group and animal, so each run of an animal is
+	// appended to the current group.
+	groups := []*growthGroup{}
+	for i := 0; i < len(rows); {
+		j := i
+		for j < len(rows) && rows[j].animal == rows[i].animal {
+			j++
+		}
+		r := rows[i]
+		if n := len(groups); n == 0 || groups[n-1].Breed != r.breed || groups[n-1].PurityLevel != r.purityLevel {
+			groups = append(groups, &growthGroup{Breed: r.breed, PurityLevel: r.purityLevel, Animals: []*growth{}})
+		}
+		g := groups[len(groups)-1]
+		g.Animals = append(g.Animals, animalGrowth(rows[i:j]))
+		i = j
+	}
+	for _, g := range groups {
+		var adgs, adjusted []float64
+		for _, a := range g.Animals {
+			if a.ADG != nil {
+				adgs = append(adgs, *a.ADG)
+			}
+			if a.Adjusted205 != nil {
+				adjusted = append(adjusted, *a.Adjusted205)
+			}
+		}
+		g.AvgADG, g.AvgAdjusted205 = average(adgs), average(adjusted)
+	}
+	return groups, nil
+}
+
+// animalGrowth computes the figures of one animal from its weighings, in date order.
+func animalGrowth(rows []*growthRow) *growth {
+	first, last := rows[0], rows[len(rows)-1]
+	g := &growth{Animal: first.animal, Name: first.name, Weighings: len(rows)}
+	if days := daysBetween(first.date, last.date); days > 0 {
+		g.ADG = round((last.weight - first.weight) / float64(days))
+	}
+	var birth, weaning *growthRow
+	for _, r := range rows {
+		if r.stage == nil {
+			continue
+		}
+		if *r.stage == StageBirth && birth == nil {
+			birth = r
+		}
+		if *r.stage == StageWeaning && weaning == nil {
+			weaning = r
+		}
+	}
+	if weaning == nil {
+		return g
+	}
+	age := daysBetween(first.birth, weaning.date)
+	g.WeaningAge = &age
+	if age < minWeaningAge || age > maxWeaningAge {
+		return g
+	}
+	bw := defaultBirthWeight
+	if birth != nil {
+		bw = birth.weight
+	} else {
+		g.BirthWeightEstimated = true
+	}
+	g.Adjusted205 = round((weaning.weight-bw)/float64(age)*adjustedAge + bw)
+	return g
+}
+
+func daysBetween(from, to date.Date) int {
+	return int(math.Round(to.Sub(from.Time).Hours() / 24))
+}
+
+func average(vs []float64) *float64 {
+	if len(vs) == 0 {
+		return nil
+	}
+	sum := 0.0
+	for _, v := range vs {
+		sum += v
+	}
+	return round(sum / float64(len(vs)))
+}
+
+func round(v float64) *float64 {
+	r := math.Round(v*100) / 100
+	return &r
+}
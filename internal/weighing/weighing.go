@@ -0,0 +1,103 @@
+// Package weighing implements the weighing function: the weights of each
+// animal and the growth figures derived from them, average daily gain and
+// adjusted 205-day weaning weight.
+package weighing
+
+import (
+	"database/sql"
+	"strings"
+
+	"fazendadojuca.com.br/internal/api"
+	"fazendadojuca.com.br/internal/database"
+	"fazendadojuca.com.br/internal/date"
+	"fazendadojuca.com.br/internal/repository"
+	"fazendadojuca.com.br/internal/validation"
+)
+
+// Stages of the weighings growth is computed from.
+const (
+	StageBirth    = "birth"
+	StageWeaning  = "weaning"
+	StageYearling = "yearling"
+)
+
+var (
+	stages  = []string{StageBirth, StageWeaning, StageYearling}
+	methods = []string{"scale", "tape", "visual"}
+)
+
+// weighing is a single weight of animal, in kilograms. ConditionScore is the
+// body condition score on the 1 to 9 scale.
+type weighing struct {
+	ID             int       `json:"id,omitempty"`
+	Animal         int       `json:"animal"`
+	Date           date.Date `json:"date"`
+	Weight         float64   `json:"weight"`
+	ConditionScore *float64  `json:"condition_score"`
+	Method         string    `json:"method"`
+	Stage          *string   `json:"stage"`
+	api.Meta
+}
+
+var weighingRepo = repository.New(repository.Entity[weighing]{
+	Name:    "Weighing",
+	Table:   "weighing",
+	Columns: []string{"animal", "date", "weight", "condition_score", "method", "stage"},
+	ID:      func(w *weighing) *int { return &w.ID },
+	Fields: func(w *weighing) []interface{} {
+		return []interface{}{&w.Animal, &w.Date, &w.Weight, &w.ConditionScore, &w.Method, &w.Stage}
+	},
+	Validate: validate,
+})
+
+func validate(w *weighing) error {
+	if w.Animal == 0 {
+		return api.Validation("Animal Required")
+	}
+	if w.Date.IsZero() {
+		return api.Validation("Invalid Date")
+	}
+	if w.Date.After(date.Today().Time) {
+		return api.Validation("Date In The Future")
+	}
+	if w.Weight <= 0 {
+		return api.Validation("Invalid Weight")
+	}
+	if s := w.ConditionScore; s != nil && (*s < 1 || *s > 9) {
+		return api.Validation("Invalid Condition Score")
+	}
+	if w.Method == "" {
+		w.Method = methods[0]
+	}
+	if !validation.OneOf(w.Method, methods) {
+		return api.Validation("Invalid Method")
+	}
+	if w.Stage != nil && !validation.OneOf(*w.Stage, stages) {
+		return api.Validation("Invalid Stage")
+	}
+	var birth date.Date
+	db := database.DB()
+	err := db.QueryRow("SELECT birth FROM animal WHERE id = ?", w.Animal).Scan(&birth)
+	if err == sql.ErrNoRows {
+		return api.Validation("Animal Not Found")
+	}
+	if err != nil {
+		return api.Internal(err)
+	}
+	if w.Date.Before(birth.Time) {
+		return api.Validation("Date Before Birth")
+	}
+	return nil
+}
+
+var crud = api.Handler[weighing](weighingRepo)
+
+// Handler is the API Gateway handler of the weighing function.
+var Handler = api.Recover(handler)
+
+func handler(req api.Request) (*api.Response, error) {
+	if req.HTTPMethod == "GET" && strings.HasSuffix(req.Path, "/growth") {
+		return getGrowth(req)
+	}
+	return crud(req)
+}
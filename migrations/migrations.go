@@ -0,0 +1,20 @@
+// Package migrations embeds the numbered schema migrations applied by cmd/migrate,
+// one directory per SQL dialect. Both dialects keep the same version numbers.
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed mysql/*.sql sqlite/*.sql
+var files embed.FS
+
+// For returns the NNNN_name.up.sql and NNNN_name.down.sql files of dialect.
+func For(dialect string) fs.FS {
+	sub, err := fs.Sub(files, dialect)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS `animal`;
+DROP TABLE IF EXISTS `purity_level`;
+DROP TABLE IF EXISTS `breed`;
+DROP TABLE IF EXISTS `gender`;
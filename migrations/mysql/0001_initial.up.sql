@@ -0,0 +1,55 @@
+-- Tables as first forward engineered from db_model.mwb.
+
+CREATE TABLE IF NOT EXISTS `gender` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `name` VARCHAR(45) NOT NULL,
+  PRIMARY KEY (`id`))
+ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `breed` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `name` VARCHAR(45) NOT NULL,
+  PRIMARY KEY (`id`))
+ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `purity_level` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `level` VARCHAR(45) NOT NULL,
+  PRIMARY KEY (`id`))
+ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `animal` (
+  `ID` INT NOT NULL AUTO_INCREMENT,
+  `name` VARCHAR(45) NOT NULL,
+  `number` VARCHAR(255) NOT NULL,
+  `registry` VARCHAR(255) NOT NULL,
+  `origin` VARCHAR(255) NOT NULL,
+  `father` INT NOT NULL,
+  `mother` INT NOT NULL,
+  `insemination` TINYINT NOT NULL,
+  `birth` DATE NOT NULL,
+  `death` DATE NOT NULL,
+  `gender_id` INT NOT NULL,
+  `breed_id` INT NOT NULL,
+  `purity_level_id` INT NOT NULL,
+  PRIMARY KEY (`ID`))
+ENGINE = InnoDB;
+
+INSERT INTO `gender` (`id`, `name`) VALUES
+  (1, 'Macho'),
+  (2, 'Fêmea');
+
+INSERT INTO `breed` (`id`, `name`) VALUES
+  (1, 'Desconhecida'),
+  (2, 'Aberdeen Angus'),
+  (3, 'American Angus'),
+  (4, 'Black Angus'),
+  (5, 'Red Angus');
+
+INSERT INTO `purity_level` (`id`, `level`) VALUES
+  (1, '1'),
+  (2, '1/2'),
+  (3, '3/4'),
+  (4, '7/8'),
+  (5, '15/16'),
+  (6, '31/32');
@@ -0,0 +1,20 @@
+ALTER TABLE `animal`
+  DROP FOREIGN KEY `fk_animal_gender`,
+  DROP FOREIGN KEY `fk_animal_breed`,
+  DROP FOREIGN KEY `fk_animal_purity_level`,
+  DROP FOREIGN KEY `fk_animal_father`,
+  DROP FOREIGN KEY `fk_animal_mother`;
+
+ALTER TABLE `animal`
+  DROP INDEX `fk_animal_gender_idx`,
+  DROP INDEX `fk_animal_breed_idx`,
+  DROP INDEX `fk_animal_purity_level_idx`,
+  DROP INDEX `fk_animal_father_idx`,
+  DROP INDEX `fk_animal_mother_idx`;
+
+UPDATE `animal` SET `father` = 0 WHERE `father` IS NULL;
+UPDATE `animal` SET `mother` = 0 WHERE `mother` IS NULL;
+
+ALTER TABLE `animal`
+  MODIFY `father` INT NOT NULL,
+  MODIFY `mother` INT NOT NULL;
@@ -0,0 +1,41 @@
+
+ALTER TABLE `animal`
+  MODIFY `father` INT NULL,
+  MODIFY `mother` INT NULL;
+
+-- Unknown parents were stored as 0 and any id that no longer exists is
+-- just as unknown.
+UPDATE `animal` a LEFT JOIN `animal` f ON f.`ID` = a.`father` SET a.`father` = NULL WHERE f.`ID` IS NULL;
+UPDATE `animal` a LEFT JOIN `animal` m ON m.`ID` = a.`mother` SET a.`mother` = NULL WHERE m.`ID` IS NULL;
+
+ALTER TABLE `animal`
+  ADD INDEX `fk_animal_gender_idx` (`gender_id` ASC),
+  ADD INDEX `fk_animal_breed_idx` (`breed_id` ASC),
+  ADD INDEX `fk_animal_purity_level_idx` (`purity_level_id` ASC),
+  ADD INDEX `fk_animal_father_idx` (`father` ASC),
+  ADD INDEX `fk_animal_mother_idx` (`mother` ASC),
+  ADD CONSTRAINT `fk_animal_gender`
+    FOREIGN KEY (`gender_id`)
+    REFERENCES `gender` (`id`)
+    ON DELETE RESTRICT
+    ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_animal_breed`
+    FOREIGN KEY (`breed_id`)
+    REFERENCES `breed` (`id`)
+    ON DELETE RESTRICT
+    ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_animal_purity_level`
+    FOREIGN KEY (`purity_level_id`)
+    REFERENCES `purity_level` (`id`)
+    ON DELETE RESTRICT
+    ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_animal_father`
+    FOREIGN KEY (`father`)
+    REFERENCES `animal` (`ID`)
+    ON DELETE RESTRICT
+    ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_animal_mother`
+    FOREIGN KEY (`mother`)
+    REFERENCES `animal` (`ID`)
+    ON DELETE RESTRICT
+    ON UPDATE CASCADE;
@@ -0,0 +1,6 @@
+-- Restores the zero death date of live animals, which needs a sql_mode
+-- without NO_ZERO_DATE like the one the original schema ran under.
+UPDATE `animal` SET `death` = '0000-00-00' WHERE `death` IS NULL;
+
+ALTER TABLE `animal`
+  MODIFY `death` DATE NOT NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE `animal`
+  MODIFY `death` DATE NULL;
+
+-- Live animals were stored with a zero death date.
+UPDATE `animal` SET `death` = NULL WHERE YEAR(`death`) = 0;
@@ -0,0 +1,11 @@
+ALTER TABLE `animal`
+  DROP FOREIGN KEY `fk_animal_external_father`,
+  DROP FOREIGN KEY `fk_animal_external_mother`;
+
+ALTER TABLE `animal`
+  DROP INDEX `fk_animal_external_father_idx`,
+  DROP INDEX `fk_animal_external_mother_idx`,
+  DROP `external_father`,
+  DROP `external_mother`;
+
+DROP TABLE IF EXISTS `external_parent`;
@@ -0,0 +1,38 @@
+-- Parents that are not part of the herd, such as semen-bank bulls known only
+-- by their registry number.
+CREATE TABLE IF NOT EXISTS `external_parent` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `name` VARCHAR(45) NOT NULL,
+  `registry` VARCHAR(255) NOT NULL,
+  `breed_id` INT NOT NULL,
+  `purity_level_id` INT NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_external_parent_breed_idx` (`breed_id` ASC),
+  INDEX `fk_external_parent_purity_level_idx` (`purity_level_id` ASC),
+  CONSTRAINT `fk_external_parent_breed`
+    FOREIGN KEY (`breed_id`)
+    REFERENCES `breed` (`id`)
+    ON DELETE RESTRICT
+    ON UPDATE CASCADE,
+  CONSTRAINT `fk_external_parent_purity_level`
+    FOREIGN KEY (`purity_level_id`)
+    REFERENCES `purity_level` (`id`)
+    ON DELETE RESTRICT
+    ON UPDATE CASCADE)
+ENGINE = InnoDB;
+
+ALTER TABLE `animal`
+  ADD `external_father` INT NULL AFTER `mother`,
+  ADD `external_mother` INT NULL AFTER `external_father`,
+  ADD INDEX `fk_animal_external_father_idx` (`external_father` ASC),
+  ADD INDEX `fk_animal_external_mother_idx` (`external_mother` ASC),
+  ADD CONSTRAINT `fk_animal_external_father`
+    FOREIGN KEY (`external_father`)
+    REFERENCES `external_parent` (`id`)
+    ON DELETE RESTRICT
+    ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_animal_external_mother`
+    FOREIGN KEY (`external_mother`)
+    REFERENCES `external_parent` (`id`)
+    ON DELETE RESTRICT
+    ON UPDATE CASCADE;
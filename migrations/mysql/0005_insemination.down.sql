@@ -0,0 +1,8 @@
+ALTER TABLE `animal`
+  DROP FOREIGN KEY `fk_animal_insemination`;
+
+ALTER TABLE `animal`
+  DROP INDEX `fk_animal_insemination_idx`,
+  DROP `insemination_id`;
+
+DROP TABLE IF EXISTS `insemination`;
@@ -0,0 +1,40 @@
+-- Artificial insemination services. The bull is either a herd animal or an
+-- external parent, and calves point back to the service they came from.
+CREATE TABLE IF NOT EXISTS `insemination` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `date` DATE NOT NULL,
+  `cow` INT NOT NULL,
+  `bull` INT NULL,
+  `external_bull` INT NULL,
+  `technician` VARCHAR(255) NOT NULL,
+  `straw_batch` VARCHAR(255) NOT NULL,
+  `outcome` VARCHAR(16) NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_insemination_cow_idx` (`cow` ASC),
+  INDEX `fk_insemination_bull_idx` (`bull` ASC),
+  INDEX `fk_insemination_external_bull_idx` (`external_bull` ASC),
+  CONSTRAINT `fk_insemination_cow`
+    FOREIGN KEY (`cow`)
+    REFERENCES `animal` (`ID`)
+    ON DELETE RESTRICT
+    ON UPDATE CASCADE,
+  CONSTRAINT `fk_insemination_bull`
+    FOREIGN KEY (`bull`)
+    REFERENCES `animal` (`ID`)
+    ON DELETE RESTRICT
+    ON UPDATE CASCADE,
+  CONSTRAINT `fk_insemination_external_bull`
+    FOREIGN KEY (`external_bull`)
+    REFERENCES `external_parent` (`id`)
+    ON DELETE RESTRICT
+    ON UPDATE CASCADE)
+ENGINE = InnoDB;
+
+ALTER TABLE `animal`
+  ADD `insemination_id` INT NULL AFTER `insemination`,
+  ADD INDEX `fk_animal_insemination_idx` (`insemination_id` ASC),
+  ADD CONSTRAINT `fk_animal_insemination`
+    FOREIGN KEY (`insemination_id`)
+    REFERENCES `insemination` (`id`)
+    ON DELETE RESTRICT
+    ON UPDATE CASCADE;
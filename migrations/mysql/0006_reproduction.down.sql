@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS `reproduction_event`;
+
+ALTER TABLE `breed`
+  DROP `gestation_days`;
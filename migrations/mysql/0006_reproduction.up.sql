@@ -0,0 +1,52 @@
+-- Gestation length used for expected calving dates, 283 days when NULL.
+ALTER TABLE `breed`
+  ADD `gestation_days` INT NULL;
+
+-- Reproduction events of each cow: heat, service (natural or AI), pregnancy
+-- check and calving.
+CREATE TABLE IF NOT EXISTS `reproduction_event` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `cow` INT NOT NULL,
+  `date` DATE NOT NULL,
+  `type` VARCHAR(16) NOT NULL,
+  `method` VARCHAR(16) NULL,
+  `bull` INT NULL,
+  `external_bull` INT NULL,
+  `insemination_id` INT NULL,
+  `expected_calving` DATE NULL,
+  `pregnant` TINYINT NULL,
+  `calving_outcome` VARCHAR(16) NULL,
+  `calf` INT NULL,
+  `notes` VARCHAR(255) NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_reproduction_event_cow_idx` (`cow` ASC, `date` ASC),
+  INDEX `fk_reproduction_event_bull_idx` (`bull` ASC),
+  INDEX `fk_reproduction_event_external_bull_idx` (`external_bull` ASC),
+  INDEX `fk_reproduction_event_insemination_idx` (`insemination_id` ASC),
+  INDEX `fk_reproduction_event_calf_idx` (`calf` ASC),
+  CONSTRAINT `fk_reproduction_event_cow`
+    FOREIGN KEY (`cow`)
+    REFERENCES `animal` (`ID`)
+    ON DELETE RESTRICT
+    ON UPDATE CASCADE,
+  CONSTRAINT `fk_reproduction_event_bull`
+    FOREIGN KEY (`bull`)
+    REFERENCES `animal` (`ID`)
+    ON DELETE RESTRICT
+    ON UPDATE CASCADE,
+  CONSTRAINT `fk_reproduction_event_external_bull`
+    FOREIGN KEY (`external_bull`)
+    REFERENCES `external_parent` (`id`)
+    ON DELETE RESTRICT
+    ON UPDATE CASCADE,
+  CONSTRAINT `fk_reproduction_event_insemination`
+    FOREIGN KEY (`insemination_id`)
+    REFERENCES `insemination` (`id`)
+    ON DELETE RESTRICT
+    ON UPDATE CASCADE,
+  CONSTRAINT `fk_reproduction_event_calf`
+    FOREIGN KEY (`calf`)
+    REFERENCES `animal` (`ID`)
+    ON DELETE RESTRICT
+    ON UPDATE CASCADE)
+ENGINE = InnoDB;
@@ -0,0 +1,18 @@
+-- Weights of each animal. stage marks the birth, weaning and yearling
+-- weighings the growth figures are computed from.
+CREATE TABLE IF NOT EXISTS `weighing` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `animal` INT NOT NULL,
+  `date` DATE NOT NULL,
+  `weight` DECIMAL(7,2) NOT NULL,
+  `condition_score` DECIMAL(3,1) NULL,
+  `method` VARCHAR(16) NOT NULL,
+  `stage` VARCHAR(16) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_weighing_animal_idx` (`animal` ASC, `date` ASC),
+  CONSTRAINT `fk_weighing_animal`
+    FOREIGN KEY (`animal`)
+    REFERENCES `animal` (`ID`)
+    ON DELETE RESTRICT
+    ON UPDATE CASCADE)
+ENGINE = InnoDB;
@@ -0,0 +1,22 @@
+-- Vaccinations, dewormings and treatments of each animal. Meat and milk of an
+-- animal may not be sold until its withdrawal_end.
+CREATE TABLE IF NOT EXISTS `health_event` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `animal` INT NOT NULL,
+  `date` DATE NOT NULL,
+  `type` VARCHAR(16) NOT NULL,
+  `product` VARCHAR(255) NOT NULL,
+  `dose` VARCHAR(45) NOT NULL,
+  `route` VARCHAR(16) NOT NULL,
+  `vet` VARCHAR(255) NOT NULL,
+  `withdrawal_end` DATE NULL,
+  `notes` VARCHAR(255) NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_health_event_animal_idx` (`animal` ASC, `date` ASC),
+  INDEX `health_event_withdrawal_end_idx` (`withdrawal_end` ASC),
+  CONSTRAINT `fk_health_event_animal`
+    FOREIGN KEY (`animal`)
+    REFERENCES `animal` (`ID`)
+    ON DELETE RESTRICT
+    ON UPDATE CASCADE)
+ENGINE = InnoDB;
@@ -0,0 +1,35 @@
+ALTER TABLE `health_event`
+  DROP `version`,
+  DROP `updated_at`;
+
+ALTER TABLE `weighing`
+  DROP `version`,
+  DROP `updated_at`;
+
+ALTER TABLE `reproduction_event`
+  DROP `version`,
+  DROP `updated_at`;
+
+ALTER TABLE `insemination`
+  DROP `version`,
+  DROP `updated_at`;
+
+ALTER TABLE `external_parent`
+  DROP `version`,
+  DROP `updated_at`;
+
+ALTER TABLE `animal`
+  DROP `version`,
+  DROP `updated_at`;
+
+ALTER TABLE `purity_level`
+  DROP `version`,
+  DROP `updated_at`;
+
+ALTER TABLE `breed`
+  DROP `version`,
+  DROP `updated_at`;
+
+ALTER TABLE `gender`
+  DROP `version`,
+  DROP `updated_at`;
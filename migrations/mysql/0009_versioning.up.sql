@@ -0,0 +1,38 @@
+-- Optimistic concurrency: version is bumped by every update, which the API
+-- checks against If-Match.
+
+ALTER TABLE `gender`
+  ADD `version` INT NOT NULL DEFAULT 1,
+  ADD `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+ALTER TABLE `breed`
+  ADD `version` INT NOT NULL DEFAULT 1,
+  ADD `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+ALTER TABLE `purity_level`
+  ADD `version` INT NOT NULL DEFAULT 1,
+  ADD `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+ALTER TABLE `animal`
+  ADD `version` INT NOT NULL DEFAULT 1,
+  ADD `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+ALTER TABLE `external_parent`
+  ADD `version` INT NOT NULL DEFAULT 1,
+  ADD `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+ALTER TABLE `insemination`
+  ADD `version` INT NOT NULL DEFAULT 1,
+  ADD `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+ALTER TABLE `reproduction_event`
+  ADD `version` INT NOT NULL DEFAULT 1,
+  ADD `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+ALTER TABLE `weighing`
+  ADD `version` INT NOT NULL DEFAULT 1,
+  ADD `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+ALTER TABLE `health_event`
+  ADD `version` INT NOT NULL DEFAULT 1,
+  ADD `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
@@ -0,0 +1 @@
+ALTER TABLE `animal` DROP `deleted_at`;
@@ -0,0 +1,5 @@
+-- Animals are soft deleted: deleted_at hides them from the herd while their
+-- descendants keep them in their pedigree.
+
+ALTER TABLE `animal`
+  ADD `deleted_at` TIMESTAMP NULL DEFAULT NULL;
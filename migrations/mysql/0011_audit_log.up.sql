@@ -0,0 +1,14 @@
+-- Every create, update and delete made through the API: who made it, when,
+-- and the before and after values of the fields it changed, as JSON. Rows
+-- outlive the records they describe, so there is no foreign key.
+CREATE TABLE IF NOT EXISTS `audit_log` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `actor` VARCHAR(128) NOT NULL,
+  `entity` VARCHAR(45) NOT NULL,
+  `entity_id` INT NOT NULL,
+  `action` VARCHAR(16) NOT NULL,
+  `changes` TEXT NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `audit_log_entity_idx` (`entity` ASC, `entity_id` ASC))
+ENGINE = InnoDB;
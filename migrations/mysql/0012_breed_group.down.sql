@@ -0,0 +1,2 @@
+ALTER TABLE `breed` DROP `unknown`;
+ALTER TABLE `breed` DROP `breed_group`;
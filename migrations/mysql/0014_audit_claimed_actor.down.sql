@@ -0,0 +1,3 @@
+UPDATE `audit_log` SET `actor` = `claimed_actor` WHERE `actor` = 'anonymous' AND `claimed_actor` IS NOT NULL;
+
+ALTER TABLE `audit_log` DROP `claimed_actor`;
@@ -0,0 +1,48 @@
+-- SQLite version of the tables first forward engineered from db_model.mwb.
+-- Dates are kept as YYYY-MM-DD text.
+
+CREATE TABLE IF NOT EXISTS `gender` (
+  `id` INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `name` VARCHAR(45) NOT NULL);
+
+CREATE TABLE IF NOT EXISTS `breed` (
+  `id` INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `name` VARCHAR(45) NOT NULL);
+
+CREATE TABLE IF NOT EXISTS `purity_level` (
+  `id` INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `level` VARCHAR(45) NOT NULL);
+
+CREATE TABLE IF NOT EXISTS `animal` (
+  `ID` INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `name` VARCHAR(45) NOT NULL,
+  `number` VARCHAR(255) NOT NULL,
+  `registry` VARCHAR(255) NOT NULL,
+  `origin` VARCHAR(255) NOT NULL,
+  `father` INT NOT NULL,
+  `mother` INT NOT NULL,
+  `insemination` TINYINT NOT NULL,
+  `birth` TEXT NOT NULL,
+  `death` TEXT NOT NULL,
+  `gender_id` INT NOT NULL,
+  `breed_id` INT NOT NULL,
+  `purity_level_id` INT NOT NULL);
+
+INSERT INTO `gender` (`id`, `name`) VALUES
+  (1, 'Macho'),
+  (2, 'Fêmea');
+
+INSERT INTO `breed` (`id`, `name`) VALUES
+  (1, 'Desconhecida'),
+  (2, 'Aberdeen Angus'),
+  (3, 'American Angus'),
+  (4, 'Black Angus'),
+  (5, 'Red Angus');
+
+INSERT INTO `purity_level` (`id`, `level`) VALUES
+  (1, '1'),
+  (2, '1/2'),
+  (3, '3/4'),
+  (4, '7/8'),
+  (5, '15/16'),
+  (6, '31/32');
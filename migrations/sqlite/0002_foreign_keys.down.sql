@@ -0,0 +1,27 @@
+PRAGMA foreign_keys = OFF;
+
+CREATE TABLE `animal_old` (
+  `ID` INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `name` VARCHAR(45) NOT NULL,
+  `number` VARCHAR(255) NOT NULL,
+  `registry` VARCHAR(255) NOT NULL,
+  `origin` VARCHAR(255) NOT NULL,
+  `father` INT NOT NULL,
+  `mother` INT NOT NULL,
+  `insemination` TINYINT NOT NULL,
+  `birth` TEXT NOT NULL,
+  `death` TEXT NOT NULL,
+  `gender_id` INT NOT NULL,
+  `breed_id` INT NOT NULL,
+  `purity_level_id` INT NOT NULL);
+
+INSERT INTO `animal_old`
+  SELECT `ID`, `name`, `number`, `registry`, `origin`, IFNULL(`father`, 0), IFNULL(`mother`, 0),
+    `insemination`, `birth`, `death`, `gender_id`, `breed_id`, `purity_level_id`
+  FROM `animal`;
+
+DROP TABLE `animal`;
+
+ALTER TABLE `animal_old` RENAME TO `animal`;
+
+PRAGMA foreign_keys = ON;
@@ -0,0 +1,37 @@
+-- SQLite cannot change a column to NULL in place, so animal is rebuilt.
+
+PRAGMA foreign_keys = OFF;
+
+CREATE TABLE `animal_new` (
+  `ID` INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `name` VARCHAR(45) NOT NULL,
+  `number` VARCHAR(255) NOT NULL,
+  `registry` VARCHAR(255) NOT NULL,
+  `origin` VARCHAR(255) NOT NULL,
+  `father` INT NULL REFERENCES `animal` (`ID`) ON DELETE RESTRICT ON UPDATE CASCADE,
+  `mother` INT NULL REFERENCES `animal` (`ID`) ON DELETE RESTRICT ON UPDATE CASCADE,
+  `insemination` TINYINT NOT NULL,
+  `birth` TEXT NOT NULL,
+  `death` TEXT NULL,
+  `gender_id` INT NOT NULL REFERENCES `gender` (`id`) ON DELETE RESTRICT ON UPDATE CASCADE,
+  `breed_id` INT NOT NULL REFERENCES `breed` (`id`) ON DELETE RESTRICT ON UPDATE CASCADE,
+  `purity_level_id` INT NOT NULL REFERENCES `purity_level` (`id`) ON DELETE RESTRICT ON UPDATE CASCADE);
+
+-- Live animals were stored with an empty or zero death date.
+INSERT INTO `animal_new`
+  SELECT `ID`, `name`, `number`, `registry`, `origin`, `father`, `mother`, `insemination`, `birth`,
+    CASE WHEN `death` = '' OR `death` LIKE '0000%' THEN NULL ELSE `death` END,
+    `gender_id`, `breed_id`, `purity_level_id`
+  FROM `animal`;
+
+DROP TABLE `animal`;
+
+ALTER TABLE `animal_new` RENAME TO `animal`;
+
+CREATE INDEX `fk_animal_gender_idx` ON `animal` (`gender_id`);
+CREATE INDEX `fk_animal_breed_idx` ON `animal` (`breed_id`);
+CREATE INDEX `fk_animal_purity_level_idx` ON `animal` (`purity_level_id`);
+CREATE INDEX `fk_animal_father_idx` ON `animal` (`father`);
+CREATE INDEX `fk_animal_mother_idx` ON `animal` (`mother`);
+
+PRAGMA foreign_keys = ON;
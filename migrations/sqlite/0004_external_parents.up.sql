@@ -0,0 +1,19 @@
+-- Parents that are not part of the herd, such as semen-bank bulls known only
+-- by their registry number.
+CREATE TABLE IF NOT EXISTS `external_parent` (
+  `id` INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `name` VARCHAR(45) NOT NULL,
+  `registry` VARCHAR(255) NOT NULL,
+  `breed_id` INT NOT NULL REFERENCES `breed` (`id`) ON DELETE RESTRICT ON UPDATE CASCADE,
+  `purity_level_id` INT NOT NULL REFERENCES `purity_level` (`id`) ON DELETE RESTRICT ON UPDATE CASCADE);
+
+CREATE INDEX `fk_external_parent_breed_idx` ON `external_parent` (`breed_id`);
+CREATE INDEX `fk_external_parent_purity_level_idx` ON `external_parent` (`purity_level_id`);
+
+ALTER TABLE `animal` ADD `external_father` INT NULL
+  REFERENCES `external_parent` (`id`) ON DELETE RESTRICT ON UPDATE CASCADE;
+ALTER TABLE `animal` ADD `external_mother` INT NULL
+  REFERENCES `external_parent` (`id`) ON DELETE RESTRICT ON UPDATE CASCADE;
+
+CREATE INDEX `fk_animal_external_father_idx` ON `animal` (`external_father`);
+CREATE INDEX `fk_animal_external_mother_idx` ON `animal` (`external_mother`);
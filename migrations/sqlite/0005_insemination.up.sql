@@ -0,0 +1,20 @@
+-- Artificial insemination services. The bull is either a herd animal or an
+-- external parent, and calves point back to the service they came from.
+CREATE TABLE IF NOT EXISTS `insemination` (
+  `id` INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `date` TEXT NOT NULL,
+  `cow` INT NOT NULL REFERENCES `animal` (`ID`) ON DELETE RESTRICT ON UPDATE CASCADE,
+  `bull` INT NULL REFERENCES `animal` (`ID`) ON DELETE RESTRICT ON UPDATE CASCADE,
+  `external_bull` INT NULL REFERENCES `external_parent` (`id`) ON DELETE RESTRICT ON UPDATE CASCADE,
+  `technician` VARCHAR(255) NOT NULL,
+  `straw_batch` VARCHAR(255) NOT NULL,
+  `outcome` VARCHAR(16) NOT NULL);
+
+CREATE INDEX `fk_insemination_cow_idx` ON `insemination` (`cow`);
+CREATE INDEX `fk_insemination_bull_idx` ON `insemination` (`bull`);
+CREATE INDEX `fk_insemination_external_bull_idx` ON `insemination` (`external_bull`);
+
+ALTER TABLE `animal` ADD `insemination_id` INT NULL
+  REFERENCES `insemination` (`id`) ON DELETE RESTRICT ON UPDATE CASCADE;
+
+CREATE INDEX `fk_animal_insemination_idx` ON `animal` (`insemination_id`);
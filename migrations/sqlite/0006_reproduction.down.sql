@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS `reproduction_event`;
+
+ALTER TABLE `breed` DROP `gestation_days`;
@@ -0,0 +1,25 @@
+-- Gestation length used for expected calving dates, 283 days when NULL.
+ALTER TABLE `breed` ADD `gestation_days` INT NULL;
+
+-- Reproduction events of each cow: heat, service (natural or AI), pregnancy
+-- check and calving.
+CREATE TABLE IF NOT EXISTS `reproduction_event` (
+  `id` INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `cow` INT NOT NULL REFERENCES `animal` (`ID`) ON DELETE RESTRICT ON UPDATE CASCADE,
+  `date` TEXT NOT NULL,
+  `type` VARCHAR(16) NOT NULL,
+  `method` VARCHAR(16) NULL,
+  `bull` INT NULL REFERENCES `animal` (`ID`) ON DELETE RESTRICT ON UPDATE CASCADE,
+  `external_bull` INT NULL REFERENCES `external_parent` (`id`) ON DELETE RESTRICT ON UPDATE CASCADE,
+  `insemination_id` INT NULL REFERENCES `insemination` (`id`) ON DELETE RESTRICT ON UPDATE CASCADE,
+  `expected_calving` TEXT NULL,
+  `pregnant` TINYINT NULL,
+  `calving_outcome` VARCHAR(16) NULL,
+  `calf` INT NULL REFERENCES `animal` (`ID`) ON DELETE RESTRICT ON UPDATE CASCADE,
+  `notes` VARCHAR(255) NOT NULL);
+
+CREATE INDEX `fk_reproduction_event_cow_idx` ON `reproduction_event` (`cow`, `date`);
+CREATE INDEX `fk_reproduction_event_bull_idx` ON `reproduction_event` (`bull`);
+CREATE INDEX `fk_reproduction_event_external_bull_idx` ON `reproduction_event` (`external_bull`);
+CREATE INDEX `fk_reproduction_event_insemination_idx` ON `reproduction_event` (`insemination_id`);
+CREATE INDEX `fk_reproduction_event_calf_idx` ON `reproduction_event` (`calf`);
@@ -0,0 +1,12 @@
+-- Weights of each animal. stage marks the birth, weaning and yearling
+-- weighings the growth figures are computed from.
+CREATE TABLE IF NOT EXISTS `weighing` (
+  `id` INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `animal` INT NOT NULL REFERENCES `animal` (`ID`) ON DELETE RESTRICT ON UPDATE CASCADE,
+  `date` TEXT NOT NULL,
+  `weight` REAL NOT NULL,
+  `condition_score` REAL NULL,
+  `method` VARCHAR(16) NOT NULL,
+  `stage` VARCHAR(16) NULL);
+
+CREATE INDEX `fk_weighing_animal_idx` ON `weighing` (`animal`, `date`);
@@ -0,0 +1,16 @@
+-- Vaccinations, dewormings and treatments of each animal. Meat and milk of an
+-- animal may not be sold until its withdrawal_end.
+CREATE TABLE IF NOT EXISTS `health_event` (
+  `id` INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `animal` INT NOT NULL REFERENCES `animal` (`ID`) ON DELETE RESTRICT ON UPDATE CASCADE,
+  `date` TEXT NOT NULL,
+  `type` VARCHAR(16) NOT NULL,
+  `product` VARCHAR(255) NOT NULL,
+  `dose` VARCHAR(45) NOT NULL,
+  `route` VARCHAR(16) NOT NULL,
+  `vet` VARCHAR(255) NOT NULL,
+  `withdrawal_end` TEXT NULL,
+  `notes` VARCHAR(255) NOT NULL);
+
+CREATE INDEX `fk_health_event_animal_idx` ON `health_event` (`animal`, `date`);
+CREATE INDEX `health_event_withdrawal_end_idx` ON `health_event` (`withdrawal_end`);
@@ -0,0 +1,26 @@
+ALTER TABLE `health_event` DROP `updated_at`;
+ALTER TABLE `health_event` DROP `version`;
+
+ALTER TABLE `weighing` DROP `updated_at`;
+ALTER TABLE `weighing` DROP `version`;
+
+ALTER TABLE `reproduction_event` DROP `updated_at`;
+ALTER TABLE `reproduction_event` DROP `version`;
+
+ALTER TABLE `insemination` DROP `updated_at`;
+ALTER TABLE `insemination` DROP `version`;
+
+ALTER TABLE `external_parent` DROP `updated_at`;
+ALTER TABLE `external_parent` DROP `version`;
+
+ALTER TABLE `animal` DROP `updated_at`;
+ALTER TABLE `animal` DROP `version`;
+
+ALTER TABLE `purity_level` DROP `updated_at`;
+ALTER TABLE `purity_level` DROP `version`;
+
+ALTER TABLE `breed` DROP `updated_at`;
+ALTER TABLE `breed` DROP `version`;
+
+ALTER TABLE `gender` DROP `updated_at`;
+ALTER TABLE `gender` DROP `version`;
@@ -0,0 +1,40 @@
+-- Optimistic concurrency: version is bumped by every update, which the API
+-- checks against If-Match.
+-- SQLite only accepts constant defaults when adding a column, so updated_at
+-- starts at the epoch and is set right away.
+
+ALTER TABLE `gender` ADD `version` INT NOT NULL DEFAULT 1;
+ALTER TABLE `gender` ADD `updated_at` TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00';
+UPDATE `gender` SET `updated_at` = CURRENT_TIMESTAMP;
+
+ALTER TABLE `breed` ADD `version` INT NOT NULL DEFAULT 1;
+ALTER TABLE `breed` ADD `updated_at` TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00';
+UPDATE `breed` SET `updated_at` = CURRENT_TIMESTAMP;
+
+ALTER TABLE `purity_level` ADD `version` INT NOT NULL DEFAULT 1;
+ALTER TABLE `purity_level` ADD `updated_at` TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00';
+UPDATE `purity_level` SET `updated_at` = CURRENT_TIMESTAMP;
+
+ALTER TABLE `animal` ADD `version` INT NOT NULL DEFAULT 1;
+ALTER TABLE `animal` ADD `updated_at` TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00';
+UPDATE `animal` SET `updated_at` = CURRENT_TIMESTAMP;
+
+ALTER TABLE `external_parent` ADD `version` INT NOT NULL DEFAULT 1;
+ALTER TABLE `external_parent` ADD `updated_at` TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00';
+UPDATE `external_parent` SET `updated_at` = CURRENT_TIMESTAMP;
+
+ALTER TABLE `insemination` ADD `version` INT NOT NULL DEFAULT 1;
+ALTER TABLE `insemination` ADD `updated_at` TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00';
+UPDATE `insemination` SET `updated_at` = CURRENT_TIMESTAMP;
+
+ALTER TABLE `reproduction_event` ADD `version` INT NOT NULL DEFAULT 1;
+ALTER TABLE `reproduction_event` ADD `updated_at` TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00';
+UPDATE `reproduction_event` SET `updated_at` = CURRENT_TIMESTAMP;
+
+ALTER TABLE `weighing` ADD `version` INT NOT NULL DEFAULT 1;
+ALTER TABLE `weighing` ADD `updated_at` TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00';
+UPDATE `weighing` SET `updated_at` = CURRENT_TIMESTAMP;
+
+ALTER TABLE `health_event` ADD `version` INT NOT NULL DEFAULT 1;
+ALTER TABLE `health_event` ADD `updated_at` TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00';
+UPDATE `health_event` SET `updated_at` = CURRENT_TIMESTAMP;
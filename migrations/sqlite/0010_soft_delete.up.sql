@@ -0,0 +1,4 @@
+-- Animals are soft deleted: deleted_at hides them from the herd while their
+-- descendants keep them in their pedigree.
+
+ALTER TABLE `animal` ADD `deleted_at` TIMESTAMP NULL;
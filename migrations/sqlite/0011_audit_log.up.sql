@@ -0,0 +1,13 @@
+-- Every create, update and delete made through the API: who made it, when,
+-- and the before and after values of the fields it changed, as JSON. Rows
+-- outlive the records they describe, so there is no foreign key.
+CREATE TABLE IF NOT EXISTS `audit_log` (
+  `id` INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `actor` VARCHAR(128) NOT NULL,
+  `entity` VARCHAR(45) NOT NULL,
+  `entity_id` INT NOT NULL,
+  `action` VARCHAR(16) NOT NULL,
+  `changes` TEXT NOT NULL);
+
+CREATE INDEX `audit_log_entity_idx` ON `audit_log` (`entity`, `entity_id`);
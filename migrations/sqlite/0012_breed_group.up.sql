@@ -0,0 +1,8 @@
+-- Purity passes on between breeds of the same group, such as the Angus
+-- varieties, and never from or to a breed marked unknown.
+
+ALTER TABLE `breed` ADD `breed_group` VARCHAR(45) NULL;
+ALTER TABLE `breed` ADD `unknown` TINYINT NOT NULL DEFAULT 0;
+
+UPDATE `breed` SET `breed_group` = 'Angus' WHERE `name` LIKE '%Angus%';
+UPDATE `breed` SET `unknown` = 1 WHERE `id` = 1;
@@ -0,0 +1 @@
+ALTER TABLE `gender` DROP `sex`;
@@ -0,0 +1,7 @@
+-- The sex behind each gender, so sires and dams are told apart whatever the
+-- genders are named.
+
+ALTER TABLE `gender` ADD `sex` VARCHAR(8) NULL;
+
+UPDATE `gender` SET `sex` = 'male' WHERE `id` = 1;
+UPDATE `gender` SET `sex` = 'female' WHERE `id` = 2;
@@ -0,0 +1,7 @@
+-- actor only holds the principal authenticated by API Gateway. The name a
+-- client gives in its X-Actor header is kept apart, as claimed_actor. Without
+-- an authorizer, every actor recorded so far came from that header.
+
+ALTER TABLE `audit_log` ADD `claimed_actor` VARCHAR(128) NULL;
+
+UPDATE `audit_log` SET `claimed_actor` = `actor`, `actor` = 'anonymous' WHERE `actor` <> 'anonymous';
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"fazendadojuca.com.br/internal/reproduction"
+)
+
+func main() {
+	lambda.Start(reproduction.Handler)
+}
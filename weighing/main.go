@@ -0,0 +1,11 @@
+package main
+
+import (
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"fazendadojuca.com.br/internal/weighing"
+)
+
+func main() {
+	lambda.Start(weighing.Handler)
+}